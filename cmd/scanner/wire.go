@@ -9,9 +9,13 @@ package main
 
 import (
 	"github.com/gw-gong/key-spy/internal/app/scanner/crawler"
+	"github.com/gw-gong/key-spy/internal/app/scanner/differ"
+	"github.com/gw-gong/key-spy/internal/app/scanner/history"
 	"github.com/gw-gong/key-spy/internal/app/scanner/notifier"
 	"github.com/gw-gong/key-spy/internal/app/scanner/reporter"
+	"github.com/gw-gong/key-spy/internal/app/scanner/retention"
 	"github.com/gw-gong/key-spy/internal/app/scanner/service"
+	"github.com/gw-gong/key-spy/internal/app/scanner/trend"
 	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
 
 	"github.com/google/wire"
@@ -26,7 +30,11 @@ var ConfigSet = wire.NewSet(
 var BizSet = wire.NewSet(
 	crawler.NewCrawler,
 	reporter.NewReporter,
+	history.NewStore,
+	differ.NewDiffer,
+	trend.NewTrend,
 	notifier.NewNotifier,
+	retention.NewRetention,
 	service.NewScannerService,
 )
 
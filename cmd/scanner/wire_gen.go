@@ -10,9 +10,13 @@ import (
 	"github.com/google/wire"
 	"github.com/gw-gong/gwkit-go/hotcfg"
 	"github.com/gw-gong/key-spy/internal/app/scanner/crawler"
+	"github.com/gw-gong/key-spy/internal/app/scanner/differ"
+	"github.com/gw-gong/key-spy/internal/app/scanner/history"
 	"github.com/gw-gong/key-spy/internal/app/scanner/notifier"
 	"github.com/gw-gong/key-spy/internal/app/scanner/reporter"
+	"github.com/gw-gong/key-spy/internal/app/scanner/retention"
 	"github.com/gw-gong/key-spy/internal/app/scanner/service"
+	"github.com/gw-gong/key-spy/internal/app/scanner/trend"
 	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
 )
 
@@ -26,8 +30,12 @@ func InitServer(cfgOption *hotcfg.LocalConfigOption) (*Server, func(), error) {
 	hotLoaderManager := hotcfg.NewHotLoaderManager()
 	crawlerCrawler := crawler.NewCrawler(config)
 	reporterReporter := reporter.NewReporter(config)
+	store := history.NewStore(config)
+	differDiffer := differ.NewDiffer(config, store)
+	trendTrend := trend.NewTrend(config, store)
 	notifierNotifier := notifier.NewNotifier(config)
-	scannerService := service.NewScannerService(config, crawlerCrawler, reporterReporter, notifierNotifier)
+	retentionRetention := retention.NewRetention(config, store)
+	scannerService := service.NewScannerService(config, crawlerCrawler, reporterReporter, differDiffer, trendTrend, store, notifierNotifier, retentionRetention)
 	server := &Server{
 		cfg:            config,
 		hlm:            hotLoaderManager,
@@ -41,6 +49,6 @@ func InitServer(cfgOption *hotcfg.LocalConfigOption) (*Server, func(), error) {
 
 var ConfigSet = wire.NewSet(localcfg.NewConfig, hotcfg.NewHotLoaderManager)
 
-var BizSet = wire.NewSet(crawler.NewCrawler, reporter.NewReporter, notifier.NewNotifier, service.NewScannerService)
+var BizSet = wire.NewSet(crawler.NewCrawler, reporter.NewReporter, history.NewStore, differ.NewDiffer, trend.NewTrend, notifier.NewNotifier, retention.NewRetention, service.NewScannerService)
 
 var ServerSet = wire.NewSet(wire.Struct(new(Server), "*"))
@@ -4,9 +4,13 @@ go 1.23.2
 
 require (
 	github.com/PuerkitoBio/goquery v1.10.1
+	github.com/andybalholm/brotli v1.1.1
+	github.com/google/uuid v1.6.0
 	github.com/google/wire v0.6.0
 	github.com/gw-gong/gwkit-go v0.4.1-0.20260108025749-3fbd74918c50
 	github.com/robfig/cron/v3 v3.0.1
+	golang.org/x/net v0.41.0
+	golang.org/x/text v0.26.0
 )
 
 require (
@@ -30,7 +34,6 @@ require (
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/google/s2a-go v0.1.8 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/googleapis/enterprise-certificate-proxy v0.3.4 // indirect
 	github.com/googleapis/gax-go/v2 v2.14.1 // indirect
 	github.com/hashicorp/consul/api v1.29.4 // indirect
@@ -81,11 +84,9 @@ require (
 	go.uber.org/zap v1.27.0 // indirect
 	golang.org/x/crypto v0.39.0 // indirect
 	golang.org/x/exp v0.0.0-20230817173708-d852ddb80c63 // indirect
-	golang.org/x/net v0.41.0 // indirect
 	golang.org/x/oauth2 v0.25.0 // indirect
 	golang.org/x/sync v0.15.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
-	golang.org/x/text v0.26.0 // indirect
 	golang.org/x/time v0.8.0 // indirect
 	google.golang.org/api v0.215.0 // indirect
 	google.golang.org/genproto v0.0.0-20241118233622-e639e219e697 // indirect
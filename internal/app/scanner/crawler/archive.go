@@ -0,0 +1,193 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+	"github.com/gw-gong/key-spy/internal/pkg/warc"
+
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+// WARC 归档模式
+const (
+	warcModeAll  = "all"  // 归档所有处理过的响应
+	warcModeHits = "hits" // 只归档命中关键词或检出敏感数据的响应
+)
+
+// warcFieldDepth 记录页面深度的扩展字段，离线重扫时使用
+const warcFieldDepth = "Key-Spy-Depth"
+
+// httpCapture 保存写入 WARC 所需的请求与响应信息
+type httpCapture struct {
+	date     time.Time
+	response *http.Response
+}
+
+func (c *crawler) warcEnabled() bool {
+	return c.cfg.Output != nil && c.cfg.Output.WARC != nil && c.cfg.Output.WARC.Enabled
+}
+
+// openArchive 创建本次扫描的 WARC 文件
+func (c *crawler) openArchive(scanID string) error {
+	c.archive = nil
+	if !c.warcEnabled() {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.cfg.Output.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%s_%s.warc", c.cfg.Output.FilePrefix, scanID)
+	if c.cfg.Output.WARC.Compress {
+		fileName += ".gz"
+	}
+
+	writer, err := warc.NewWriter(filepath.Join(c.cfg.Output.Dir, fileName), c.cfg.Output.WARC.Compress)
+	if err != nil {
+		return err
+	}
+
+	info := fmt.Sprintf("software: key-spy\r\nformat: WARC File Format 1.1\r\nisPartOf: %s\r\n", c.cfg.Scanner.TargetURL)
+	if _, err := writer.WriteRecord(&warc.Record{
+		Type:        warc.TypeWarcinfo,
+		ContentType: "application/warc-fields",
+		Fields:      [][2]string{{"WARC-Filename", fileName}},
+		Block:       []byte(info),
+	}); err != nil {
+		writer.Close()
+		return err
+	}
+
+	c.archive = writer
+	return nil
+}
+
+// closeArchive 关闭 WARC 文件，返回文件路径
+func (c *crawler) closeArchive(ctx context.Context) string {
+	if c.archive == nil {
+		return ""
+	}
+
+	filePath := c.archive.Name()
+	if err := c.archive.Close(); err != nil {
+		log.Errorc(ctx, "Failed to close warc file", log.Err(err))
+	}
+	c.archive = nil
+
+	log.Infoc(ctx, "WARC archive written", log.Str("file_path", filePath))
+	return filePath
+}
+
+// readArchiveBody 读取不做匹配的响应体，用于归档全部响应，超出上限的部分截断
+func (c *crawler) readArchiveBody(resp *http.Response, pg *page) error {
+	body, err := newBodyReader(resp)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	pg.raw, pg.truncated, err = readLimited(body, c.bodyLimit(pg.contentType))
+	if err != nil {
+		return err
+	}
+	pg.size = int64(len(pg.raw))
+	return nil
+}
+
+// archivePage 将页面的请求与响应写入 WARC，并在结果中记录响应的记录 ID
+func (c *crawler) archivePage(ctx context.Context, pg *page, result *model.ScanResult) {
+	if c.archive == nil || pg.capture == nil {
+		return
+	}
+	// 只检出敏感数据的页面同样归档，离线重扫时才能复现这些附加发现
+	if c.cfg.Output.WARC.Mode == warcModeHits && result.TotalCount == 0 && len(result.Sensitive) == 0 {
+		return
+	}
+
+	capture := pg.capture
+	targetURI := capture.response.Request.URL.String()
+
+	body := []byte(pg.body)
+	if pg.raw != nil {
+		body = pg.raw
+	}
+
+	responseFields := [][2]string{{warcFieldDepth, strconv.Itoa(result.Depth)}}
+	if pg.truncated {
+		responseFields = append(responseFields, [2]string{"WARC-Truncated", "length"})
+	}
+
+	responseID, err := c.archive.WriteRecord(&warc.Record{
+		Type:        warc.TypeResponse,
+		TargetURI:   targetURI,
+		Date:        capture.date,
+		ContentType: "application/http;msgtype=response",
+		Payload:     body,
+		Fields:      responseFields,
+		Block:       buildResponseBlock(capture.response, body),
+	})
+	if err != nil {
+		log.Warnc(ctx, "Failed to archive response", log.Str("url", pg.url), log.Err(err))
+		return
+	}
+	result.WARCRecordID = responseID
+
+	if _, err := c.archive.WriteRecord(&warc.Record{
+		Type:         warc.TypeRequest,
+		TargetURI:    targetURI,
+		Date:         capture.date,
+		ContentType:  "application/http;msgtype=request",
+		ConcurrentTo: responseID,
+		Block:        buildRequestBlock(capture.response.Request),
+	}); err != nil {
+		log.Warnc(ctx, "Failed to archive request", log.Str("url", pg.url), log.Err(err))
+	}
+}
+
+// redactedHeaders 写入 WARC 前隐去的头部，避免归档中留下认证信息与登录会话
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactHeader 复制头部并隐去认证信息
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if len(header.Values(name)) > 0 {
+			header.Set(name, "[redacted]")
+		}
+	}
+	return header
+}
+
+// buildRequestBlock 生成请求报文（不含请求体）
+func buildRequestBlock(req *http.Request) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s HTTP/1.1\r\n", req.Method, req.URL.RequestURI())
+	fmt.Fprintf(&buf, "Host: %s\r\n", req.URL.Host)
+	redactHeader(req.Header).Write(&buf)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
+
+// buildResponseBlock 生成响应报文，响应体为解压后的内容，因此去除编码相关的头部
+func buildResponseBlock(resp *http.Response, body []byte) []byte {
+	header := redactHeader(resp.Header)
+	header.Del("Content-Encoding")
+	header.Del("Transfer-Encoding")
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s\r\n", resp.Proto, resp.Status)
+	header.Write(&buf)
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes()
+}
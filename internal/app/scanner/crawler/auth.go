@@ -0,0 +1,370 @@
+package crawler
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+const defaultMaxRelogin = 3
+
+// prepareSession 初始化本次扫描的会话：Cookie、认证信息以及表单登录
+func (c *crawler) prepareSession(ctx context.Context) error {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+	c.httpClient.Jar = jar
+
+	if err := c.setStaticCookies(); err != nil {
+		return err
+	}
+
+	authHeader, err := c.buildAuthHeader()
+	if err != nil {
+		return fmt.Errorf("failed to resolve auth config: %w", err)
+	}
+	c.authHeader = authHeader
+
+	c.loginMu.Lock()
+	c.loginGen = 0
+	c.reloginCount = 0
+	c.loginMu.Unlock()
+
+	if !c.loginEnabled() {
+		return nil
+	}
+
+	if err := c.login(ctx); err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	log.Infoc(ctx, "Login succeeded")
+
+	return nil
+}
+
+// setRequestHeaders 设置公共请求头、自定义请求头和认证头
+func (c *crawler) setRequestHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", c.cfg.Scanner.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
+
+	for key, value := range c.cfg.Scanner.Headers {
+		req.Header.Set(key, value)
+	}
+
+	if c.authHeader != "" {
+		req.Header.Set("Authorization", c.authHeader)
+	}
+}
+
+func (c *crawler) setStaticCookies() error {
+	if len(c.cfg.Scanner.Cookies) == 0 {
+		return nil
+	}
+
+	targetURL, err := url.Parse(c.cfg.Scanner.TargetURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse target url: %w", err)
+	}
+
+	// 按域名分组写入，cookiejar 会校验 Cookie 域名与 URL 是否匹配
+	cookiesByHost := make(map[string][]*http.Cookie)
+	for _, cookieCfg := range c.cfg.Scanner.Cookies {
+		host := targetURL.Host
+		if cookieCfg.Domain != "" {
+			host = strings.TrimPrefix(cookieCfg.Domain, ".")
+		}
+		path := cookieCfg.Path
+		if path == "" {
+			path = "/"
+		}
+		cookiesByHost[host] = append(cookiesByHost[host], &http.Cookie{
+			Name:   cookieCfg.Name,
+			Value:  cookieCfg.Value,
+			Domain: cookieCfg.Domain,
+			Path:   path,
+		})
+	}
+
+	for host, cookies := range cookiesByHost {
+		c.httpClient.Jar.SetCookies(&url.URL{Scheme: targetURL.Scheme, Host: host, Path: "/"}, cookies)
+	}
+
+	return nil
+}
+
+func (c *crawler) buildAuthHeader() (string, error) {
+	authCfg := c.cfg.Scanner.Auth
+	if authCfg == nil || authCfg.Type == "" {
+		return "", nil
+	}
+
+	switch strings.ToLower(authCfg.Type) {
+	case "basic":
+		password, err := authCfg.Password.Resolve()
+		if err != nil {
+			return "", err
+		}
+		credentials := base64.StdEncoding.EncodeToString([]byte(authCfg.Username + ":" + password))
+		return "Basic " + credentials, nil
+	case "bearer":
+		token, err := authCfg.Token.Resolve()
+		if err != nil {
+			return "", err
+		}
+		if token == "" {
+			return "", fmt.Errorf("bearer token is empty")
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("unsupported auth type: %s", authCfg.Type)
+	}
+}
+
+func (c *crawler) loginEnabled() bool {
+	return c.cfg.Scanner.Login != nil && c.cfg.Scanner.Login.Enabled
+}
+
+// login 执行表单登录，登录后的 Cookie 保存在会话的 cookiejar 中
+func (c *crawler) login(ctx context.Context) error {
+	loginCfg := c.cfg.Scanner.Login
+
+	values := url.Values{}
+	submitURL := loginCfg.URL
+	if loginCfg.FormURL != "" {
+		action, hiddenFields, err := c.fetchLoginForm(ctx, loginCfg.FormURL)
+		if err != nil {
+			return err
+		}
+		for name, value := range hiddenFields {
+			values.Set(name, value)
+		}
+		if submitURL == "" {
+			submitURL = action
+		}
+	}
+	if submitURL == "" {
+		return fmt.Errorf("login url is empty")
+	}
+
+	for _, field := range loginCfg.Fields {
+		value := field.Value
+		if field.Secret != nil {
+			secret, err := field.Secret.Resolve()
+			if err != nil {
+				return fmt.Errorf("failed to resolve field %s: %w", field.Name, err)
+			}
+			value = secret
+		}
+		values.Set(field.Name, value)
+	}
+
+	method := strings.ToUpper(loginCfg.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	var req *http.Request
+	var err error
+	if method == http.MethodGet {
+		// 表单字段合并到登录地址原有的查询参数中
+		target, parseErr := url.Parse(submitURL)
+		if parseErr != nil {
+			return fmt.Errorf("failed to parse login url: %w", parseErr)
+		}
+		query := target.Query()
+		for name, fieldValues := range values {
+			query[name] = fieldValues
+		}
+		target.RawQuery = query.Encode()
+		req, err = http.NewRequestWithContext(ctx, method, target.String(), nil)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, submitURL, strings.NewReader(values.Encode()))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return err
+	}
+	c.setRequestHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if !c.loginSucceeded(resp, string(body)) {
+		return fmt.Errorf("success check not satisfied, status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// fetchLoginForm 获取登录表单，返回提交地址及隐藏字段
+func (c *crawler) fetchLoginForm(ctx context.Context, formURL string) (action string, hiddenFields map[string]string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, formURL, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	c.setRequestHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse login form: %w", err)
+	}
+
+	// 优先选择包含密码输入框的表单
+	form := doc.Find("form:has(input[type=password])").First()
+	if form.Length() == 0 {
+		form = doc.Find("form").First()
+	}
+	if form.Length() == 0 {
+		return "", nil, fmt.Errorf("no form found in %s", formURL)
+	}
+
+	hiddenFields = make(map[string]string)
+	form.Find("input[type=hidden][name]").Each(func(i int, s *goquery.Selection) {
+		name, _ := s.Attr("name")
+		value, _ := s.Attr("value")
+		hiddenFields[name] = value
+	})
+
+	action = c.resolveURL(resp.Request.URL.String(), form.AttrOr("action", ""))
+	if action == "" {
+		action = resp.Request.URL.String()
+	}
+
+	return action, hiddenFields, nil
+}
+
+// loginSucceeded 判断登录是否成功，未配置判定条件时以状态码判断
+func (c *crawler) loginSucceeded(resp *http.Response, body string) bool {
+	check := c.cfg.Scanner.Login.SuccessCheck
+	if check == nil {
+		return resp.StatusCode < http.StatusBadRequest
+	}
+
+	if len(check.StatusCodes) > 0 && !containsInt(check.StatusCodes, resp.StatusCode) {
+		return false
+	}
+	if check.URLContains != "" && !strings.Contains(resp.Request.URL.String(), check.URLContains) {
+		return false
+	}
+	if check.BodyContains != "" && !strings.Contains(body, check.BodyContains) {
+		return false
+	}
+	if check.Cookie != "" && !c.hasCookie(resp.Request.URL, check.Cookie) {
+		return false
+	}
+
+	return true
+}
+
+// isLoggedOut 判断响应是否表明会话已失效
+func (c *crawler) isLoggedOut(resp *http.Response, body string) bool {
+	if !c.loginEnabled() || c.cfg.Scanner.Login.LogoutCheck == nil {
+		return false
+	}
+	check := c.cfg.Scanner.Login.LogoutCheck
+
+	if len(check.StatusCodes) > 0 && containsInt(check.StatusCodes, resp.StatusCode) {
+		return true
+	}
+	if check.URLContains != "" && strings.Contains(resp.Request.URL.String(), check.URLContains) {
+		return true
+	}
+	if check.BodyContains != "" && strings.Contains(body, check.BodyContains) {
+		return true
+	}
+	if check.Cookie != "" && !c.hasCookie(resp.Request.URL, check.Cookie) {
+		return true
+	}
+
+	return false
+}
+
+// relogin 重新登录，gen 为发起请求时的登录代数，避免并发请求重复登录
+func (c *crawler) relogin(ctx context.Context, gen int) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	if c.loginGen != gen {
+		return nil
+	}
+
+	maxRelogin := c.cfg.Scanner.Login.MaxRelogin
+	if maxRelogin <= 0 {
+		maxRelogin = defaultMaxRelogin
+	}
+	if c.reloginCount >= maxRelogin {
+		return fmt.Errorf("relogin limit reached: %d", maxRelogin)
+	}
+	c.reloginCount++
+
+	log.Warnc(ctx, "Logout detected, logging in again", log.Int("relogin_count", c.reloginCount))
+	if err := c.login(ctx); err != nil {
+		return fmt.Errorf("relogin failed: %w", err)
+	}
+	c.loginGen++
+
+	return nil
+}
+
+func (c *crawler) currentLoginGen() int {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+	return c.loginGen
+}
+
+// isSkipped 判断 URL 是否在登录配置中被排除，未启用登录时不排除
+func (c *crawler) isSkipped(pageURL string) bool {
+	if c.cfg.Scanner.Login == nil || !c.cfg.Scanner.Login.Enabled {
+		return false
+	}
+	for _, skip := range c.cfg.Scanner.Login.SkipURLs {
+		if skip != "" && strings.Contains(pageURL, skip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *crawler) hasCookie(u *url.URL, name string) bool {
+	for _, cookie := range c.httpClient.Jar.Cookies(u) {
+		if cookie.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, value int) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
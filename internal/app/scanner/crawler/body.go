@@ -0,0 +1,157 @@
+package crawler
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+const defaultMaxBodyBytes = 10 * 1024 * 1024
+
+// acceptEncoding 手动声明支持的压缩格式，此时 http.Transport 不再自动解压 gzip
+const acceptEncoding = "gzip, deflate, br"
+
+// bodyReader 包装解压后的响应体，统计读取的字节数
+type bodyReader struct {
+	reader io.Reader
+	closer io.Closer
+	n      int64
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *bodyReader) Close() error {
+	if b.closer != nil {
+		return b.closer.Close()
+	}
+	return nil
+}
+
+// newBodyReader 根据 Content-Encoding 透明解压响应体
+func newBodyReader(resp *http.Response) (*bodyReader, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	switch encoding {
+	case "", "identity":
+		return &bodyReader{reader: resp.Body}, nil
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		return &bodyReader{reader: gz, closer: gz}, nil
+	case "deflate":
+		// deflate 通常带 zlib 头，但也有服务端直接返回原始 deflate 数据
+		br := bufio.NewReader(resp.Body)
+		header, _ := br.Peek(2)
+		if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create zlib reader: %w", err)
+			}
+			return &bodyReader{reader: zr, closer: zr}, nil
+		}
+		fr := flate.NewReader(br)
+		return &bodyReader{reader: fr, closer: fr}, nil
+	case "br":
+		return &bodyReader{reader: brotli.NewReader(resp.Body)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", encoding)
+	}
+}
+
+// readLimited 最多读取 limit 字节，超出部分丢弃并返回 truncated
+func readLimited(r io.Reader, limit int64) (data []byte, truncated bool, err error) {
+	data, err = io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(data)) > limit {
+		return data[:limit], true, nil
+	}
+	return data, false, nil
+}
+
+// hasMore 判断读取到上限后是否还有剩余内容
+func hasMore(r io.Reader) bool {
+	var b [1]byte
+	n, _ := r.Read(b[:])
+	return n > 0
+}
+
+// mediaType 返回小写的媒体类型，不含参数
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+	return mt
+}
+
+func isHTML(mt string) bool {
+	return mt == "text/html" || mt == "application/xhtml+xml"
+}
+
+// matchContentType 判断媒体类型是否匹配规则，规则支持 text/* 形式的通配
+func matchContentType(mt, rule string) bool {
+	rule = strings.ToLower(strings.TrimSpace(rule))
+	if rule == "*" || rule == "*/*" || rule == mt {
+		return true
+	}
+	if strings.HasSuffix(rule, "/*") {
+		return strings.HasPrefix(mt, strings.TrimSuffix(rule, "*"))
+	}
+	return false
+}
+
+// bodyLimit 返回媒体类型对应的响应体上限，精确匹配的规则优先于通配规则
+func (c *crawler) bodyLimit(mt string) int64 {
+	limitCfg := c.cfg.Scanner.BodyLimit
+	if limitCfg == nil {
+		return defaultMaxBodyBytes
+	}
+
+	limit := limitCfg.DefaultMaxBytes
+	if limit <= 0 {
+		limit = defaultMaxBodyBytes
+	}
+
+	exact := false
+	for _, rule := range limitCfg.Rules {
+		if rule.MaxBytes <= 0 || !matchContentType(mt, rule.ContentType) {
+			continue
+		}
+		isExact := strings.EqualFold(strings.TrimSpace(rule.ContentType), mt)
+		if isExact || !exact {
+			limit = rule.MaxBytes
+			exact = isExact
+		}
+	}
+
+	return limit
+}
+
+// isStreamType 判断媒体类型是否以流式方式匹配
+func (c *crawler) isStreamType(mt string) bool {
+	if c.cfg.Scanner.BodyLimit == nil {
+		return false
+	}
+	for _, rule := range c.cfg.Scanner.BodyLimit.StreamContentTypes {
+		if matchContentType(mt, rule) {
+			return true
+		}
+	}
+	return false
+}
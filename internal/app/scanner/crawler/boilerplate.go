@@ -0,0 +1,325 @@
+package crawler
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+const (
+	defaultBoilerplateMinRatio = 0.5
+	defaultBoilerplateMinPages = 5
+	// boilerplateTextRunes 附加发现中展示的区块内容长度
+	boilerplateTextRunes = 80
+)
+
+// htmlBlock 页面中一个块级元素内的连续文本
+type htmlBlock struct {
+	start, end int    // 在页面内容中的字节偏移
+	hash       uint64 // 规范化文本的哈希
+	text       string // 截断后的区块文本，用于展示
+}
+
+// pageBlocks 页面中各区块的关键词命中，用于扫描结束后剔除公共区块中的命中
+type pageBlocks struct {
+	blocks []*htmlBlock
+	hits   map[uint64]map[string]int    // 区块 → 关键词 → 命中次数
+	hidden map[uint64]int               // 区块中隐藏文本的命中次数
+	stored map[*model.KeywordHit]uint64 // 已保留的命中明细所在的区块
+}
+
+func (c *crawler) boilerplateEnabled() bool {
+	return c.cfg.Scanner.Boilerplate != nil && c.cfg.Scanner.Boilerplate.Enabled
+}
+
+// recordBlocks 统计页面包含的区块，返回用于记录命中的区块信息
+func (c *crawler) recordBlocks(tokens []*htmlToken) *pageBlocks {
+	blocks := splitBlocks(tokens)
+
+	c.blocksMu.Lock()
+	c.htmlPages++
+	seen := make(map[uint64]bool)
+	for _, block := range blocks {
+		if !seen[block.hash] {
+			seen[block.hash] = true
+			c.blockPages[block.hash]++
+		}
+	}
+	c.blocksMu.Unlock()
+
+	return &pageBlocks{
+		blocks: blocks,
+		hits:   make(map[uint64]map[string]int),
+		hidden: make(map[uint64]int),
+		stored: make(map[*model.KeywordHit]uint64),
+	}
+}
+
+// savePageBlocks 保存有命中的页面的区块信息
+func (c *crawler) savePageBlocks(result *model.ScanResult, pb *pageBlocks) {
+	if len(pb.hits) == 0 {
+		return
+	}
+	c.blocksMu.Lock()
+	c.pageBlocks[result] = pb
+	c.blocksMu.Unlock()
+}
+
+// addHit 记录命中所在的区块，stored 表示命中明细是否被保留；
+// 区块只由文本组成，区块范围内标签属性与注释中的命中不属于区块
+func (pb *pageBlocks) addHit(hit *model.KeywordHit, stored bool) {
+	if hit.Location == model.HitLocationAttribute || hit.Location == model.HitLocationComment {
+		return
+	}
+	i := sort.Search(len(pb.blocks), func(i int) bool { return pb.blocks[i].end > hit.Offset })
+	if i == len(pb.blocks) || pb.blocks[i].start > hit.Offset {
+		return
+	}
+
+	hash := pb.blocks[i].hash
+	if pb.hits[hash] == nil {
+		pb.hits[hash] = make(map[string]int)
+	}
+	pb.hits[hash][hit.Keyword]++
+	if hit.Hidden {
+		pb.hidden[hash]++
+	}
+	if stored {
+		pb.stored[hit] = hash
+	}
+}
+
+// removeKeyword 移除关键词在所有区块中的命中，用于豁免关键词在页面中的全部命中
+func (pb *pageBlocks) removeKeyword(keyword string) {
+	for hash, counts := range pb.hits {
+		delete(counts, keyword)
+		if len(counts) == 0 {
+			delete(pb.hits, hash)
+		}
+	}
+}
+
+// removeHit 移除一条已保留的命中明细，用于豁免单个命中
+func (pb *pageBlocks) removeHit(hit *model.KeywordHit) {
+	hash, ok := pb.stored[hit]
+	if !ok {
+		return
+	}
+	delete(pb.stored, hit)
+	if hit.Hidden {
+		pb.hidden[hash]--
+	}
+	if counts := pb.hits[hash]; counts != nil {
+		counts[hit.Keyword]--
+		if counts[hit.Keyword] <= 0 {
+			delete(counts, hit.Keyword)
+		}
+		if len(counts) == 0 {
+			delete(pb.hits, hash)
+		}
+	}
+}
+
+// siteBlock 命中关键词的公共区块
+type siteBlock struct {
+	hash   uint64
+	text   string
+	url    string // 示例页面
+	counts map[string]int
+	hidden int
+}
+
+// suppressBoilerplate 将出现在大部分页面中的区块内的命中从页面结果中移除，每个公共区块汇总为一条附加发现；
+// 需要在豁免规则之后执行，被豁免的命中不再计入公共区块
+func (c *crawler) suppressBoilerplate(results []*model.ScanResult) ([]*model.ScanResult, []*model.Finding) {
+	if !c.boilerplateEnabled() || c.htmlPages == 0 {
+		return results, nil
+	}
+
+	minRatio := c.cfg.Scanner.Boilerplate.MinRatio
+	if minRatio <= 0 {
+		minRatio = defaultBoilerplateMinRatio
+	}
+	minPages := c.cfg.Scanner.Boilerplate.MinPages
+	if minPages <= 0 {
+		minPages = defaultBoilerplateMinPages
+	}
+	isBoilerplate := func(hash uint64) bool {
+		pages := c.blockPages[hash]
+		return pages >= minPages && float64(pages)/float64(c.htmlPages) >= minRatio
+	}
+
+	sites := make(map[uint64]*siteBlock)
+	kept := make([]*model.ScanResult, 0, len(results))
+	for _, result := range results {
+		pb := c.pageBlocks[result]
+		if pb == nil {
+			kept = append(kept, result)
+			continue
+		}
+
+		for hash, counts := range pb.hits {
+			if !isBoilerplate(hash) {
+				continue
+			}
+
+			site := sites[hash]
+			if site == nil {
+				site = &siteBlock{hash: hash, url: result.URL, counts: make(map[string]int)}
+				for _, block := range pb.blocks {
+					if block.hash == hash {
+						site.text = block.text
+						break
+					}
+				}
+				sites[hash] = site
+			}
+			if result.URL < site.url {
+				site.url = result.URL
+			}
+
+			for keyword, count := range counts {
+				site.counts[keyword] += count
+				result.KeywordCounts[keyword] -= count
+				result.TotalCount -= count
+				if result.KeywordCounts[keyword] <= 0 {
+					delete(result.KeywordCounts, keyword)
+				}
+			}
+			site.hidden += pb.hidden[hash]
+			result.HiddenCount -= pb.hidden[hash]
+		}
+
+		hits := result.Hits[:0]
+		for _, hit := range result.Hits {
+			if hash, ok := pb.stored[hit]; ok && isBoilerplate(hash) {
+				continue
+			}
+			hits = append(hits, hit)
+		}
+		result.Hits = hits
+
+		keywords := result.Keywords[:0]
+		for _, keyword := range result.Keywords {
+			if result.KeywordCounts[keyword] > 0 {
+				keywords = append(keywords, keyword)
+			}
+		}
+		result.Keywords = keywords
+
+		if result.TotalCount > 0 || len(result.Sensitive) > 0 {
+			kept = append(kept, result)
+		}
+	}
+
+	return kept, c.boilerplateFindings(sites)
+}
+
+func (c *crawler) boilerplateFindings(sites map[uint64]*siteBlock) []*model.Finding {
+	list := make([]*siteBlock, 0, len(sites))
+	for _, site := range sites {
+		list = append(list, site)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if c.blockPages[list[i].hash] != c.blockPages[list[j].hash] {
+			return c.blockPages[list[i].hash] > c.blockPages[list[j].hash]
+		}
+		return list[i].text < list[j].text
+	})
+
+	findings := make([]*model.Finding, 0, len(list))
+	for _, site := range list {
+		keywords := make([]string, 0, len(site.counts))
+		for _, keyword := range c.cfg.Scanner.Keywords {
+			if site.counts[keyword] > 0 {
+				keywords = append(keywords, c.p.T("finding.keyword_times", keyword, site.counts[keyword]))
+			}
+		}
+
+		finding := &model.Finding{
+			Type:     model.FindingTypeBoilerplate,
+			Severity: model.SeverityMedium,
+			URL:      site.url,
+			Key:      strconv.FormatUint(site.hash, 16),
+			Title:    c.p.T("finding.boilerplate.title", c.blockPages[site.hash]),
+			Detail:   c.p.T("finding.boilerplate.detail", strings.Join(keywords, c.p.T("list.separator")), site.text),
+		}
+		// 公共区块中的隐藏文本通常是整站被植入的 SEO 垃圾内容
+		if site.hidden > 0 {
+			finding.Severity = model.SeverityHigh
+			finding.Detail += c.p.T("finding.boilerplate.hidden", site.hidden)
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// splitBlocks 按块级元素边界将页面文本拆分为区块，脚本与样式内容各自作为一个区块
+func splitBlocks(tokens []*htmlToken) []*htmlBlock {
+	blocks := make([]*htmlBlock, 0)
+
+	var sb strings.Builder
+	start, end := -1, -1
+	flush := func() {
+		if start < 0 {
+			return
+		}
+		text := strings.Join(strings.Fields(strings.ToLower(sb.String())), " ")
+		if text != "" {
+			h := fnv.New64a()
+			h.Write([]byte(text))
+			if runes := []rune(text); len(runes) > boilerplateTextRunes {
+				text = string(runes[:boilerplateTextRunes]) + "…"
+			}
+			blocks = append(blocks, &htmlBlock{start: start, end: end, hash: h.Sum64(), text: text})
+		}
+		sb.Reset()
+		start, end = -1, -1
+	}
+
+	rawText := ""
+	for _, tok := range tokens {
+		switch tok.typ {
+		case html.TextToken:
+			if rawText != "" {
+				flush()
+			}
+			if start < 0 {
+				start = tok.start
+			}
+			end = tok.end
+			sb.WriteString(tok.token.Data)
+			sb.WriteString(" ")
+			if rawText != "" {
+				flush()
+			}
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			rawText = ""
+			if tok.typ == html.StartTagToken && isRawTextTag(tok.token.Data) {
+				rawText = tok.token.Data
+			}
+			if isBlockElement(tok.token.Data) || rawText != "" {
+				flush()
+			}
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+func isBlockElement(tag string) bool {
+	switch tag {
+	case "address", "article", "aside", "blockquote", "body", "br", "dd", "div", "dl", "dt",
+		"fieldset", "figcaption", "figure", "footer", "form", "h1", "h2", "h3", "h4", "h5", "h6",
+		"head", "header", "hr", "html", "li", "main", "nav", "ol", "p", "pre", "section",
+		"table", "tbody", "td", "tfoot", "th", "thead", "title", "tr", "ul":
+		return true
+	}
+	return false
+}
@@ -10,4 +10,7 @@ import (
 type Crawler interface {
 	// Scan 扫描目标网站，返回扫描报告
 	Scan(ctx context.Context) (*model.ScanReport, error)
+	// Subscribe 订阅扫描过程中逐个产生的页面结果，buffer 为通道缓冲大小，缓冲已满时丢弃结果而不阻塞扫描；
+	// 调用返回的 cancel 取消订阅并关闭通道
+	Subscribe(buffer int) (results <-chan *model.ScanResult, cancel func())
 }
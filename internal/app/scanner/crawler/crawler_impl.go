@@ -1,17 +1,24 @@
 package crawler
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"regexp"
+	"os"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/gw-gong/key-spy/internal/app/scanner/detector"
+	"github.com/gw-gong/key-spy/internal/app/scanner/matcher"
 	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
 	"github.com/gw-gong/key-spy/internal/pkg/model"
+	"github.com/gw-gong/key-spy/internal/pkg/warc"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gw-gong/gwkit-go/log"
@@ -20,28 +27,55 @@ import (
 type crawler struct {
 	cfg        *localcfg.Config
 	httpClient *http.Client
-	visited    map[string]bool
+	visited    map[string]visitMode
 	visitedMu  sync.Mutex
-	results    []*model.ScanResult
-	resultsMu  sync.Mutex
 	semaphore  chan struct{}
+	matcher    matcher.Matcher
+	detectors  []detector.Detector
+	exemptions []*exemption
+	archive    *warc.Writer
+
+	// 结果流，页面处理完成后立即输出，报告也由同一结果流汇总生成
+	resultCh       chan *model.ScanResult
+	resultsDone    chan struct{}       // 消费者处理完所有结果后关闭
+	results        []*model.ScanResult // 消费者收集的结果，resultsDone 关闭前只由消费者访问
+	stream         *os.File            // 本次扫描的结果流文件，只由消费者访问
+	subscribers    map[chan *model.ScanResult]struct{}
+	subscribersMu  sync.Mutex
+	droppedResults int64 // 订阅者缓冲已满时丢弃的结果数
+
+	scanID      string        // 本次扫描的标识（开始时间），用于生成输出文件名
+	p           *i18n.Printer // 附加发现使用的语言与时区
+	evidenceSeq int64
+
+	// 会话状态，每次扫描开始时重置
+	authHeader   string
+	loginMu      sync.Mutex
+	loginGen     int
+	reloginCount int
+	localRoot    string // 本地目录扫描的根目录，为空表示扫描网站
+	certs        map[string]*x509.Certificate
+	certsMu      sync.Mutex
+
+	// 标准输入中的 URL 列表，只读取一次
+	stdinOnce     sync.Once
+	stdinSeedURLs []string
+	stdinErr      error
+
+	// 公共区块统计，每次扫描开始时重置
+	blockPages map[uint64]int                    // 区块 → 包含该区块的页面数
+	htmlPages  int                               // 统计过区块的 HTML 页面数
+	pageBlocks map[*model.ScanResult]*pageBlocks // 有命中的页面的区块信息
+	blocksMu   sync.Mutex
 }
 
 func NewCrawler(cfg *localcfg.Config) Crawler {
 	return &crawler{
-		cfg: cfg,
-		httpClient: &http.Client{
-			Timeout: time.Duration(cfg.Scanner.RequestTimeoutMs) * time.Millisecond,
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				if len(via) >= 10 {
-					return http.ErrUseLastResponse
-				}
-				return nil
-			},
-		},
-		visited:   make(map[string]bool),
-		results:   make([]*model.ScanResult, 0),
-		semaphore: make(chan struct{}, cfg.Scanner.MaxConcurrent),
+		cfg:         cfg,
+		visited:     make(map[string]visitMode),
+		semaphore:   make(chan struct{}, cfg.Scanner.MaxConcurrent),
+		certs:       make(map[string]*x509.Certificate),
+		subscribers: make(map[chan *model.ScanResult]struct{}),
 	}
 }
 
@@ -54,25 +88,77 @@ func (c *crawler) Scan(ctx context.Context) (*model.ScanReport, error) {
 		log.Int("max_depth", c.cfg.Scanner.MaxDepth),
 	)
 
-	// 开始爬取
-	var wg sync.WaitGroup
-	wg.Add(1)
-	go c.crawl(ctx, c.cfg.Scanner.TargetURL, 0, &wg)
-	wg.Wait()
+	c.visitedMu.Lock()
+	c.visited = make(map[string]visitMode)
+	c.visitedMu.Unlock()
+	c.certsMu.Lock()
+	c.certs = make(map[string]*x509.Certificate)
+	c.certsMu.Unlock()
+	c.blocksMu.Lock()
+	c.blockPages = make(map[uint64]int)
+	c.htmlPages = 0
+	c.pageBlocks = make(map[*model.ScanResult]*pageBlocks)
+	c.blocksMu.Unlock()
+
+	detectors, err := detector.NewDetectors(c.cfg.Scanner.Detectors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create detectors: %w", err)
+	}
+	c.detectors = detectors
+	exemptions, err := newExemptions(c.cfg.Scanner.Exemptions)
+	if err != nil {
+		return nil, err
+	}
+	c.exemptions = exemptions
+	c.p, err = i18n.NewPrinter(c.cfg.Scanner.Locale.Resolve(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create printer: %w", err)
+	}
+	c.matcher = matcher.NewMatcher(c.cfg.Scanner)
+	c.scanID = startTime.Format("20060102_150405")
+	c.evidenceSeq = 0
+
+	if err := c.openStream(ctx); err != nil {
+		return nil, fmt.Errorf("failed to open result stream: %w", err)
+	}
+
+	var (
+		scanMode string
+		warcFile string
+	)
+	if c.offlineEnabled() {
+		scanMode = model.ScanModeOffline
+		warcFile, err = c.scanOffline(ctx)
+	} else {
+		scanMode, warcFile, err = c.scanOnline(ctx)
+	}
+	streamFile := c.closeStream(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	endTime := time.Now()
 	duration := endTime.Sub(startTime)
 
-	// 构建报告
-	c.resultsMu.Lock()
+	// 由结果流收集的页面结果构建报告，公共区块与豁免规则只作用于这里，不影响已输出的结果流
 	matchResults := make([]*model.ScanResult, 0)
+	scanErrors := make([]*model.ScanError, 0)
+	fetched := make(map[string]bool, len(c.results))
 	for _, r := range c.results {
-		if r.TotalCount > 0 {
+		if r.Error != "" {
+			scanErrors = append(scanErrors, &model.ScanError{URL: r.URL, Depth: r.Depth, Error: r.Error})
+			continue
+		}
+		fetched[r.URL] = true
+		if r.TotalCount > 0 || len(r.Sensitive) > 0 {
 			matchResults = append(matchResults, r)
 		}
 	}
 	totalPages := len(c.results)
-	c.resultsMu.Unlock()
+
+	matchResults, suppressed, expiredFindings := c.applyExemptions(ctx, matchResults, endTime)
+	matchResults, boilerplateFindings := c.suppressBoilerplate(matchResults)
+	matchPages := keywordPages(matchResults)
 
 	report := &model.ScanReport{
 		TargetURL:  c.cfg.Scanner.TargetURL,
@@ -81,20 +167,109 @@ func (c *crawler) Scan(ctx context.Context) (*model.ScanReport, error) {
 		EndTime:    endTime.Format("2006-01-02 15:04:05"),
 		Duration:   duration.String(),
 		TotalPages: totalPages,
-		MatchPages: len(matchResults),
+		MatchPages: matchPages,
 		Results:    matchResults,
+		ErrorCount: len(scanErrors),
+		Errors:     scanErrors,
+		Findings:   c.certExpiryFindings(endTime),
+		WARCFile:   warcFile,
+		StreamFile: streamFile,
+		ScanMode:   scanMode,
+		Suppressed: suppressed,
+
+		FetchedURLs: fetched,
+	}
+	report.Findings = append(report.Findings, boilerplateFindings...)
+	report.Findings = append(report.Findings, expiredFindings...)
+	report.Findings = append(report.Findings, hiddenTextFindings(c.p, matchResults)...)
+	report.Findings = append(report.Findings, c.sensitiveFindings(matchResults)...)
+	if c.evidenceSeq > 0 {
+		report.EvidenceDir = c.evidenceDir()
 	}
 
 	log.Infoc(ctx, "Scan completed",
 		log.Int("total_pages", totalPages),
-		log.Int("match_pages", len(matchResults)),
+		log.Int("match_pages", matchPages),
+		log.Int("error_count", len(scanErrors)),
 		log.Str("duration", duration.String()),
 	)
 
 	return report, nil
 }
 
-func (c *crawler) crawl(ctx context.Context, pageURL string, depth int, wg *sync.WaitGroup) {
+// keywordPages 统计命中关键词的页面数，只检出敏感数据的页面不计入
+func keywordPages(results []*model.ScanResult) int {
+	count := 0
+	for _, result := range results {
+		if result.TotalCount > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// scanOnline 爬取网站或本地目录，返回扫描模式及本次写入的 WARC 文件
+func (c *crawler) scanOnline(ctx context.Context) (string, string, error) {
+	// 每次扫描重新创建客户端，使传输层配置的热更新生效
+	if c.httpClient != nil {
+		c.httpClient.CloseIdleConnections()
+	}
+	httpClient, err := c.newHTTPClient()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create http client: %w", err)
+	}
+	c.httpClient = httpClient
+
+	localRoot, isLocal, err := resolveLocalRoot(c.cfg.Scanner.TargetURL)
+	if err != nil {
+		return "", "", err
+	}
+	c.localRoot = localRoot
+
+	if !isLocal {
+		if err := c.prepareSession(ctx); err != nil {
+			return "", "", err
+		}
+	}
+
+	if err := c.openArchive(c.scanID); err != nil {
+		return "", "", fmt.Errorf("failed to open warc archive: %w", err)
+	}
+
+	// 开始爬取
+	var wg sync.WaitGroup
+	if !c.cfg.Scanner.SeedOnly && c.cfg.Scanner.TargetURL != "" {
+		if isLocal {
+			log.Infoc(ctx, "Scanning local directory", log.Str("root", localRoot))
+			if err := c.walkLocal(ctx, &wg); err != nil {
+				wg.Wait()
+				c.closeArchive(ctx)
+				return "", "", fmt.Errorf("failed to walk local directory: %w", err)
+			}
+		} else {
+			wg.Add(1)
+			go c.crawl(ctx, c.cfg.Scanner.TargetURL, 0, false, &wg)
+		}
+	}
+
+	// 扫描 URL 列表
+	if err := c.crawlSeeds(ctx, &wg); err != nil {
+		wg.Wait()
+		c.closeArchive(ctx)
+		return "", "", fmt.Errorf("failed to read seed urls: %w", err)
+	}
+	wg.Wait()
+
+	scanMode := model.ScanModeOnline
+	if isLocal {
+		scanMode = model.ScanModeLocal
+	}
+
+	return scanMode, c.closeArchive(ctx), nil
+}
+
+// crawl 爬取页面，listed 表示页面来自 URL 列表，此时不校验域名也不跟随链接
+func (c *crawler) crawl(ctx context.Context, pageURL string, depth int, listed bool, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	// 检查深度限制
@@ -108,20 +283,35 @@ func (c *crawler) crawl(ctx context.Context, pageURL string, depth int, wg *sync
 		return
 	}
 
-	// 检查是否已访问
-	c.visitedMu.Lock()
-	if c.visited[normalizedURL] {
-		c.visitedMu.Unlock()
+	// 检查是否被排除
+	if c.isSkipped(normalizedURL) {
+		return
+	}
+
+	// 检查 URL 是否属于目标域名，需在记录访问之前判断，避免站外链接占用 URL 列表中的页面
+	if !listed && !c.isSameDomain(normalizedURL) {
 		return
 	}
-	c.visited[normalizedURL] = true
-	c.visitedMu.Unlock()
 
-	// 检查 URL 是否属于目标域名
-	if !c.isSameDomain(normalizedURL) {
+	if isFileURL(normalizedURL) && !c.isLocalScanned(normalizedURL) {
 		return
 	}
 
+	// 检查是否已访问；只作为 URL 列表扫描过的页面被爬取到时，仍需跟随其中的链接
+	mode := visitFollowed
+	if listed {
+		mode = visitListed
+	}
+	c.visitedMu.Lock()
+	previous := c.visited[normalizedURL]
+	if previous >= mode {
+		c.visitedMu.Unlock()
+		return
+	}
+	c.visited[normalizedURL] = mode
+	c.visitedMu.Unlock()
+	linksOnly := previous == visitListed
+
 	// 获取信号量
 	select {
 	case c.semaphore <- struct{}{}:
@@ -130,16 +320,22 @@ func (c *crawler) crawl(ctx context.Context, pageURL string, depth int, wg *sync
 		return
 	}
 
-	// 请求间隔
-	time.Sleep(time.Duration(c.cfg.Scanner.RequestIntervalMs) * time.Millisecond)
+	// 请求间隔，本地文件无需等待
+	if !isFileURL(normalizedURL) {
+		time.Sleep(time.Duration(c.cfg.Scanner.RequestIntervalMs) * time.Millisecond)
+	}
 
 	log.Debugc(ctx, "Crawling page", log.Str("url", normalizedURL), log.Int("depth", depth))
 
 	// 获取页面内容
-	body, links, err := c.fetchPage(ctx, normalizedURL)
+	pg, err := c.fetchPage(ctx, normalizedURL)
+	if err != nil && linksOnly {
+		log.Warnc(ctx, "Failed to fetch page for links", log.Str("url", normalizedURL), log.Err(err))
+		return
+	}
 	if err != nil {
 		log.Warnc(ctx, "Failed to fetch page", log.Str("url", normalizedURL), log.Err(err))
-		c.addResult(&model.ScanResult{
+		c.addResult(ctx, &model.ScanResult{
 			URL:   normalizedURL,
 			Depth: depth,
 			Error: err.Error(),
@@ -147,103 +343,242 @@ func (c *crawler) crawl(ctx context.Context, pageURL string, depth int, wg *sync
 		return
 	}
 
+	// 页面结果已在 URL 列表扫描时记录，这里只跟随链接
+	if !linksOnly {
+		c.processPage(ctx, pg, depth)
+	}
+
+	if listed {
+		return
+	}
+
+	// 递归爬取链接
+	for _, link := range pg.links {
+		absoluteURL := c.resolveURL(normalizedURL, link)
+		if absoluteURL != "" {
+			wg.Add(1)
+			go c.crawl(ctx, absoluteURL, depth+1, false, wg)
+		}
+	}
+}
+
+// processPage 对已获取的页面进行关键词匹配并记录结果
+func (c *crawler) processPage(ctx context.Context, pg *page, depth int) {
+	if pg.truncated {
+		log.Warnc(ctx, "Response body truncated",
+			log.Str("url", pg.url),
+			log.Str("content_type", pg.contentType),
+			log.Int64("body_size", pg.size),
+		)
+	}
+
 	// 搜索关键词
-	result := c.searchKeywords(normalizedURL, body, depth)
-	c.addResult(result)
+	result := c.searchKeywords(pg, depth)
+	c.saveEvidence(ctx, pg, result)
+	c.archivePage(ctx, pg, result)
+	c.addResult(ctx, result)
+
+	if len(result.Sensitive) > 0 {
+		log.Warnc(ctx, "Found sensitive data",
+			log.Str("url", pg.url),
+			log.Int("count", len(result.Sensitive)),
+		)
+	}
 
 	if result.TotalCount > 0 {
 		log.Infoc(ctx, "Found keywords",
-			log.Str("url", normalizedURL),
+			log.Str("url", pg.url),
 			log.Any("keywords", result.Keywords),
 			log.Int("total_count", result.TotalCount),
+			log.Int("hidden_count", result.HiddenCount),
 		)
 	}
+}
 
-	// 递归爬取链接
-	for _, link := range links {
-		absoluteURL := c.resolveURL(normalizedURL, link)
-		if absoluteURL != "" {
-			wg.Add(1)
-			go c.crawl(ctx, absoluteURL, depth+1, wg)
-		}
+// page 表示一次抓取得到的页面
+type page struct {
+	url         string
+	contentType string                // 媒体类型，不含参数
+	body        string                // HTML 内容，流式处理的页面为空
+	links       []string              // 页面中的链接
+	matches     []*matcher.Match      // 流式处理时得到的关键词命中
+	detections  []*detector.Detection // 流式处理时得到的敏感数据命中
+	streamed    bool                  // 是否以流式方式处理
+	raw         []byte                // 流式处理或只归档的响应体，仅在需要写入 WARC 或证据快照时保留
+	fetchedAt   time.Time             // 抓取时间
+	status      string                // HTTP 状态行，本地文件为空
+	header      http.Header           // HTTP 响应头，本地文件为空
+	capture     *httpCapture          // 写入 WARC 所需的请求与响应信息
+	recordID    string                // 离线重扫时页面在来源 WARC 中的记录 ID
+	size        int64                 // 读取的响应体字节数（解压后）
+	truncated   bool                  // 是否因超出上限被截断
+}
+
+// visitMode 页面的访问方式，跟随链接的访问覆盖只扫描页面本身的访问
+type visitMode int
+
+const (
+	visitListed   visitMode = iota + 1 // 作为 URL 列表中的页面扫描，不跟随链接
+	visitFollowed                      // 扫描并跟随链接
+)
+
+func (c *crawler) fetchPage(ctx context.Context, pageURL string) (*page, error) {
+	if isFileURL(pageURL) {
+		return c.fetchLocalFile(pageURL)
 	}
+
+	gen := c.currentLoginGen()
+	pg, loggedOut, err := c.doFetchPage(ctx, pageURL)
+	if err != nil || !loggedOut {
+		return pg, err
+	}
+
+	// 会话失效，重新登录后重试一次
+	if err := c.relogin(ctx, gen); err != nil {
+		return nil, err
+	}
+	pg, loggedOut, err = c.doFetchPage(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if loggedOut {
+		return nil, fmt.Errorf("still logged out after relogin")
+	}
+	return pg, nil
 }
 
-func (c *crawler) fetchPage(ctx context.Context, pageURL string) (body string, links []string, err error) {
+func (c *crawler) doFetchPage(ctx context.Context, pageURL string) (pg *page, loggedOut bool, err error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
 	if err != nil {
-		return "", nil, err
+		return nil, false, err
 	}
 
-	req.Header.Set("User-Agent", c.cfg.Scanner.UserAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8")
+	c.setRequestHeaders(req)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", nil, err
+		return nil, false, err
 	}
 	defer resp.Body.Close()
 
-	// 只处理 HTML 内容
-	contentType := resp.Header.Get("Content-Type")
-	if !strings.Contains(contentType, "text/html") && !strings.Contains(contentType, "application/xhtml") {
-		return "", nil, nil
+	c.recordCertificate(resp)
+
+	pg = &page{
+		url:         pageURL,
+		contentType: mediaType(resp.Header.Get("Content-Type")),
+		fetchedAt:   time.Now(),
+		status:      resp.Proto + " " + resp.Status,
+		header:      resp.Header.Clone(),
+	}
+	if c.archive != nil {
+		pg.capture = &httpCapture{date: time.Now(), response: resp}
+	}
+
+	// 只处理 HTML 内容以及配置为流式匹配的内容类型，其他响应只在归档全部响应时读取响应体
+	html := isHTML(pg.contentType)
+	if !html && !c.isStreamType(pg.contentType) {
+		if pg.capture != nil && c.cfg.Output.WARC.Mode != warcModeHits {
+			if err := c.readArchiveBody(resp, pg); err != nil {
+				return nil, false, err
+			}
+		}
+		return pg, c.isLoggedOut(resp, ""), nil
 	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	body, err := newBodyReader(resp)
 	if err != nil {
-		return "", nil, err
+		return nil, false, err
+	}
+	defer body.Close()
+
+	limit := c.bodyLimit(pg.contentType)
+
+	if !html {
+		// 流式匹配，响应体不完整保留在内存中
+		pg.streamed = true
+		var reader io.Reader = io.LimitReader(body, limit)
+		var raw *bytes.Buffer
+		if pg.capture != nil || c.evidenceEnabled() {
+			raw = &bytes.Buffer{}
+			reader = io.TeeReader(reader, raw)
+		}
+		sensitive := detector.NewStreamWriter(c.detectors)
+		reader = io.TeeReader(reader, sensitive)
+		pg.matches, err = c.matcher.MatchReader(reader)
+		if err != nil {
+			return nil, false, err
+		}
+		pg.detections = sensitive.Detections()
+		if raw != nil {
+			pg.raw = raw.Bytes()
+		}
+		pg.size = body.n
+		pg.truncated = pg.size >= limit && hasMore(body)
+		return pg, c.isLoggedOut(resp, ""), nil
 	}
 
-	body = string(bodyBytes)
+	bodyBytes, truncated, err := readLimited(body, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	pg.size = int64(len(bodyBytes))
+	pg.truncated = truncated
+	pg.body = string(bodyBytes)
+
+	if c.isLoggedOut(resp, pg.body) {
+		return nil, true, nil
+	}
 
 	// 解析链接
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(pg.body))
 	if err != nil {
-		return body, nil, nil
+		return pg, false, nil
 	}
 
-	links = make([]string, 0)
+	pg.links = make([]string, 0)
 	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
 		if href, exists := s.Attr("href"); exists {
-			links = append(links, href)
+			pg.links = append(pg.links, href)
 		}
 	})
 
-	return body, links, nil
+	return pg, false, nil
 }
 
-func (c *crawler) searchKeywords(pageURL, body string, depth int) *model.ScanResult {
+func (c *crawler) searchKeywords(pg *page, depth int) *model.ScanResult {
 	result := &model.ScanResult{
-		URL:           pageURL,
+		URL:           pg.url,
 		KeywordCounts: make(map[string]int),
 		Keywords:      make([]string, 0),
 		Depth:         depth,
+		ContentType:   pg.contentType,
+		BodySize:      pg.size,
+		Truncated:     pg.truncated,
+		WARCRecordID:  pg.recordID,
 	}
 
-	for _, keyword := range c.cfg.Scanner.Keywords {
-		// 使用正则表达式进行不区分大小写的搜索
-		re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(keyword))
-		matches := re.FindAllString(body, -1)
-		count := len(matches)
+	if !pg.streamed {
+		pg.matches = c.matcher.Match(pg.body)
+	}
+
+	for _, match := range pg.matches {
+		result.KeywordCounts[match.Keyword]++
+		result.TotalCount++
+	}
+	c.buildHits(pg, result)
+	c.detectSensitive(pg, result)
 
-		if count > 0 {
-			result.KeywordCounts[keyword] = count
+	// 保持与配置一致的关键词顺序
+	for _, keyword := range c.cfg.Scanner.Keywords {
+		if result.KeywordCounts[keyword] > 0 {
 			result.Keywords = append(result.Keywords, keyword)
-			result.TotalCount += count
 		}
 	}
 
 	return result
 }
 
-func (c *crawler) addResult(result *model.ScanResult) {
-	c.resultsMu.Lock()
-	c.results = append(c.results, result)
-	c.resultsMu.Unlock()
-}
-
 func (c *crawler) normalizeURL(rawURL string) string {
 	parsed, err := url.Parse(rawURL)
 	if err != nil {
@@ -252,6 +587,11 @@ func (c *crawler) normalizeURL(rawURL string) string {
 
 	// 移除 fragment
 	parsed.Fragment = ""
+	// 本地文件的查询参数不影响文件内容，去掉后按文件路径去重
+	if parsed.Scheme == "file" {
+		parsed.RawQuery = ""
+		parsed.ForceQuery = false
+	}
 
 	// 确保有 scheme
 	if parsed.Scheme == "" {
@@ -262,6 +602,10 @@ func (c *crawler) normalizeURL(rawURL string) string {
 }
 
 func (c *crawler) isSameDomain(pageURL string) bool {
+	if c.localRoot != "" {
+		return c.isInLocalRoot(pageURL)
+	}
+
 	targetParsed, err := url.Parse(c.cfg.Scanner.TargetURL)
 	if err != nil {
 		return false
@@ -298,6 +642,10 @@ func (c *crawler) resolveURL(baseURL, href string) string {
 		return ""
 	}
 
+	if base.Scheme == "file" {
+		return c.resolveLocalURL(base, ref)
+	}
+
 	resolved := base.ResolveReference(ref)
 	resolved.Fragment = ""
 
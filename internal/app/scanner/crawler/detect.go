@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"strings"
+
+	"github.com/gw-gong/key-spy/internal/app/scanner/detector"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// maxSensitiveFindingValues 每条敏感数据发现中列出的脱敏值数量
+const maxSensitiveFindingValues = 5
+
+// detectSensitive 使用启用的检测器查找页面中的敏感数据
+func (c *crawler) detectSensitive(pg *page, result *model.ScanResult) {
+	if len(c.detectors) == 0 {
+		return
+	}
+
+	detections := pg.detections
+	if !pg.streamed {
+		detections = detector.DetectAll(c.detectors, pg.body)
+	}
+
+	for _, detection := range detections {
+		result.Sensitive = append(result.Sensitive, &model.SensitiveHit{
+			Detector: detection.Detector,
+			Value:    detection.Masked,
+			Offset:   detection.Start,
+		})
+	}
+}
+
+// sensitiveFindings 按页面和检测器汇总敏感数据发现
+func (c *crawler) sensitiveFindings(results []*model.ScanResult) []*model.Finding {
+	findings := make([]*model.Finding, 0)
+	for _, result := range results {
+		if len(result.Sensitive) == 0 {
+			continue
+		}
+
+		grouped := make(map[string][]string)
+		for _, hit := range result.Sensitive {
+			grouped[hit.Detector] = append(grouped[hit.Detector], hit.Value)
+		}
+
+		// 保持与配置一致的检测器顺序
+		for _, d := range c.detectors {
+			values := grouped[d.Name()]
+			if len(values) == 0 {
+				continue
+			}
+
+			detail := uniqueStrings(values)
+			if len(detail) > maxSensitiveFindingValues {
+				detail = append(detail[:maxSensitiveFindingValues], "…")
+			}
+			findings = append(findings, &model.Finding{
+				Type:     model.FindingTypeSensitive,
+				Severity: d.Severity(),
+				URL:      result.URL,
+				Key:      d.Name(),
+				Title:    c.p.T("finding.sensitive.title", len(values), d.Name()),
+				Detail:   strings.Join(detail, c.p.T("detail.separator")),
+			})
+		}
+	}
+	return findings
+}
+
+func uniqueStrings(list []string) []string {
+	seen := make(map[string]bool)
+	unique := make([]string, 0, len(list))
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			unique = append(unique, s)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,328 @@
+package crawler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/app/scanner/matcher"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+
+	"github.com/gw-gong/gwkit-go/log"
+	xhtml "golang.org/x/net/html"
+)
+
+const maxSnapshotNameLen = 80
+
+var snapshotNameReplacer = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+const markStyle = `<style>mark.key-spy-hit{background:#ffeb3b;color:#000;outline:2px solid #f44336;}</style>`
+
+func (c *crawler) evidenceEnabled() bool {
+	return c.cfg.Output != nil && c.cfg.Output.Evidence != nil && c.cfg.Output.Evidence.Enabled
+}
+
+// evidenceDir 返回本次扫描的证据目录
+func (c *crawler) evidenceDir() string {
+	return filepath.Join(c.cfg.Output.Dir, fmt.Sprintf("%s_%s_evidence", c.cfg.Output.FilePrefix, c.scanID))
+}
+
+// saveEvidence 为命中关键词的页面保存证据快照：原始内容、高亮副本、响应头，并记录 SHA-256 摘要
+func (c *crawler) saveEvidence(ctx context.Context, pg *page, result *model.ScanResult) {
+	if !c.evidenceEnabled() || result.TotalCount == 0 {
+		return
+	}
+
+	raw := pg.raw
+	if !pg.streamed {
+		raw = []byte(pg.body)
+	}
+	if raw == nil {
+		return
+	}
+
+	digest := sha256.Sum256(raw)
+	result.SHA256 = hex.EncodeToString(digest[:])
+
+	index := atomic.AddInt64(&c.evidenceSeq, 1)
+	dir := filepath.Join(c.evidenceDir(), fmt.Sprintf("%04d_%s", index, snapshotName(pg.url)))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Warnc(ctx, "Failed to create evidence directory", log.Str("url", pg.url), log.Err(err))
+		return
+	}
+
+	banner := fmt.Sprintf("<!-- key-spy evidence | url: %s | sha256: %s | captured: %s -->\n%s\n",
+		commentSafe(pg.url), result.SHA256, pg.fetchedAt.Format(time.RFC3339), markStyle)
+
+	rawName := "raw.html"
+	marked := markHTML(pg.body, pg.matches, banner)
+	if !isHTML(pg.contentType) {
+		rawName = "raw.txt"
+		marked = banner + markText(string(raw), pg.matches)
+	}
+
+	files := map[string][]byte{
+		rawName:       raw,
+		"marked.html": []byte(marked),
+		"headers.txt": []byte(formatEvidenceHeaders(pg, result.SHA256)),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			log.Warnc(ctx, "Failed to write evidence file", log.Str("url", pg.url), log.Str("file", name), log.Err(err))
+			return
+		}
+	}
+
+	result.SnapshotDir = dir
+}
+
+func formatEvidenceHeaders(pg *page, digest string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("URL: %s\n", pg.url))
+	sb.WriteString(fmt.Sprintf("Fetched-At: %s\n", pg.fetchedAt.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("SHA-256: %s\n", digest))
+	if pg.truncated {
+		sb.WriteString(fmt.Sprintf("Truncated: %d bytes\n", pg.size))
+	}
+	sb.WriteString("\n")
+
+	if pg.status != "" {
+		sb.WriteString(pg.status + "\n")
+	}
+	names := make([]string, 0, len(pg.header))
+	for name := range pg.header {
+		// 避免会话 Cookie 写入证据
+		if name != "Set-Cookie" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range pg.header[name] {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", name, value))
+		}
+	}
+
+	return sb.String()
+}
+
+// snapshotName 根据 URL 生成可用作目录名的字符串
+func snapshotName(pageURL string) string {
+	name := pageURL
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+	}
+	name = strings.Trim(snapshotNameReplacer.ReplaceAllString(name, "_"), "_")
+	if len(name) > maxSnapshotNameLen {
+		name = name[:maxSnapshotNameLen]
+	}
+	if name == "" {
+		name = "page"
+	}
+	return name
+}
+
+// commentSafe 转义写入 HTML 注释的 URL，-- 替换为等价的百分号编码，避免提前结束注释
+func commentSafe(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "--", "%2D%2D")
+}
+
+// removedContainers 清理副本中连同内容一起移除的元素，可执行脚本或加载外部内容
+var removedContainers = map[string]bool{
+	"script":   true,
+	"iframe":   true,
+	"object":   true,
+	"applet":   true,
+	"frameset": true,
+}
+
+// removedVoids 清理副本中移除的空元素：嵌入外部内容、改变相对链接的解析基准
+var removedVoids = map[string]bool{
+	"embed": true,
+	"base":  true,
+	"frame": true,
+}
+
+// unsafeURLSchemes 清理副本中移除的属性值前缀
+var unsafeURLSchemes = []string{"javascript:", "vbscript:", "data:"}
+
+// markHTML 生成清理后的 HTML 副本：移除脚本、内嵌框架与对象、<base>、自动跳转的 <meta>、事件属性
+// 及 javascript:/vbscript:/data: 链接，并用 <mark> 包裹正文中的关键词命中；
+// banner 插入在 <body> 之后，页面没有 <body> 时插入在文档类型声明之后，避免浏览器进入怪异模式
+func markHTML(body string, matches []*matcher.Match, banner string) string {
+	var sb strings.Builder
+	sb.Grow(len(body) + len(banner) + len(matches)*32)
+
+	next := 0      // 下一个待处理的命中
+	skipDepth := 0 // 位于移除的元素内
+	inRawText := false
+	injected := false
+	doctypeEnd := 0 // 文档类型声明在输出中的结束位置
+	for _, tok := range tokenizeHTML(body) {
+		// 跳过完全位于当前单元之前的命中
+		for next < len(matches) && matches[next].End <= tok.start {
+			next++
+		}
+
+		switch tok.typ {
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			// 浏览器忽略非空元素的自闭合写法，<iframe/> 之后的内容同样位于元素内
+			if removedContainers[tok.token.Data] {
+				skipDepth++
+				continue
+			}
+			if removedVoids[tok.token.Data] || isMetaRefresh(tok.token) {
+				continue
+			}
+			if skipDepth == 0 {
+				sb.WriteString(cleanTag(tok))
+				if !injected && tok.token.Data == "body" {
+					sb.WriteString(banner)
+					injected = true
+				}
+			}
+			inRawText = tok.typ == xhtml.StartTagToken && isRawTextTag(tok.token.Data)
+		case xhtml.EndTagToken:
+			inRawText = false
+			if removedContainers[tok.token.Data] {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if skipDepth == 0 {
+				sb.WriteString(tok.raw)
+			}
+		case xhtml.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			if inRawText {
+				sb.WriteString(tok.raw)
+				continue
+			}
+			sb.WriteString(markSegment(tok.raw, tok.start, matches[next:]))
+		default:
+			if skipDepth == 0 {
+				sb.WriteString(tok.raw)
+				if tok.typ == xhtml.DoctypeToken && doctypeEnd == 0 {
+					doctypeEnd = sb.Len()
+				}
+			}
+		}
+	}
+
+	marked := sb.String()
+	if !injected {
+		marked = marked[:doctypeEnd] + banner + marked[doctypeEnd:]
+	}
+	return marked
+}
+
+// markText 将纯文本转义为 HTML，并用 <mark> 包裹关键词命中
+func markText(text string, matches []*matcher.Match) string {
+	var sb strings.Builder
+	sb.WriteString("<pre>")
+	last := 0
+	for _, match := range matches {
+		if match.Start < last || match.End > len(text) {
+			continue
+		}
+		sb.WriteString(html.EscapeString(text[last:match.Start]))
+		sb.WriteString(`<mark class="key-spy-hit">`)
+		sb.WriteString(html.EscapeString(text[match.Start:match.End]))
+		sb.WriteString("</mark>")
+		last = match.End
+	}
+	sb.WriteString(html.EscapeString(text[last:]))
+	sb.WriteString("</pre>")
+	return sb.String()
+}
+
+// markSegment 在原文片段中插入 <mark>，offset 为片段在原文中的偏移；跨越片段边界的命中只标记片段内的部分
+func markSegment(raw string, offset int, matches []*matcher.Match) string {
+	var sb strings.Builder
+	last := 0
+	for _, match := range matches {
+		start := match.Start - offset
+		end := match.End - offset
+		if start >= len(raw) {
+			break
+		}
+		if start < last {
+			start = last
+		}
+		if end > len(raw) {
+			end = len(raw)
+		}
+		if start >= end {
+			continue
+		}
+		sb.WriteString(raw[last:start])
+		sb.WriteString(`<mark class="key-spy-hit">`)
+		sb.WriteString(raw[start:end])
+		sb.WriteString("</mark>")
+		last = end
+	}
+	sb.WriteString(raw[last:])
+	return sb.String()
+}
+
+// isMetaRefresh 判断是否为自动跳转的 <meta http-equiv="refresh">
+func isMetaRefresh(token xhtml.Token) bool {
+	if token.Data != "meta" {
+		return false
+	}
+	for _, attr := range token.Attr {
+		if attr.Key == "http-equiv" && strings.EqualFold(strings.TrimSpace(attr.Val), "refresh") {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnsafeURL 判断属性值是否为脚本或内联数据链接，浏览器会忽略协议名中的空白与控制字符，比较前一并去除
+func isUnsafeURL(val string) bool {
+	scheme := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, val)
+	scheme = strings.ToLower(scheme)
+	for _, prefix := range unsafeURLSchemes {
+		if strings.HasPrefix(scheme, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanTag 去除标签中的事件属性与脚本、内联数据链接，未修改时保留原文
+func cleanTag(tok *htmlToken) string {
+	dirty := false
+	attrs := make([]xhtml.Attribute, 0, len(tok.token.Attr))
+	for _, attr := range tok.token.Attr {
+		if strings.HasPrefix(attr.Key, "on") || isUnsafeURL(attr.Val) {
+			dirty = true
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+	if !dirty {
+		return tok.raw
+	}
+
+	token := tok.token
+	token.Attr = attrs
+	return token.String()
+}
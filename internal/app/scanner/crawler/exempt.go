@@ -0,0 +1,193 @@
+package crawler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+// exemptionDateLayout 豁免规则有效期的日期格式
+const exemptionDateLayout = "2006-01-02"
+
+// exemption 解析后的豁免规则
+type exemption struct {
+	cfg     *localcfg.ExemptionConfig
+	url     *regexp.Regexp
+	expires time.Time // 零值表示长期有效，否则在该日期结束后失效
+}
+
+// newExemptions 解析豁免规则，URL 模式中的 * 匹配任意字符
+func newExemptions(configs []*localcfg.ExemptionConfig) ([]*exemption, error) {
+	exemptions := make([]*exemption, 0, len(configs))
+	for _, cfg := range configs {
+		if cfg == nil || cfg.URL == "" {
+			continue
+		}
+
+		parts := strings.Split(cfg.URL, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		e := &exemption{
+			cfg: cfg,
+			url: regexp.MustCompile("^" + strings.Join(parts, ".*") + "$"),
+		}
+
+		if cfg.Expires != "" {
+			expires, err := time.ParseInLocation(exemptionDateLayout, cfg.Expires, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expires of exemption %s: %w", cfg.URL, err)
+			}
+			e.expires = expires.AddDate(0, 0, 1)
+		}
+		exemptions = append(exemptions, e)
+	}
+	return exemptions, nil
+}
+
+func (e *exemption) matchHit(hit *model.KeywordHit) bool {
+	return (e.cfg.Keyword == "" || e.cfg.Keyword == hit.Keyword) && hit.SnippetHash == e.cfg.SnippetHash
+}
+
+// applyExemptions 从页面结果中移除被豁免的命中，返回保留的结果、被豁免命中的统计以及已过期规则的附加发现
+func (c *crawler) applyExemptions(ctx context.Context, results []*model.ScanResult, now time.Time) ([]*model.ScanResult, []*model.Suppression, []*model.Finding) {
+	active := make([]*exemption, 0, len(c.exemptions))
+	expired := make([]*model.Finding, 0)
+	for _, e := range c.exemptions {
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			log.Warnc(ctx, "Exemption expired",
+				log.Str("url", e.cfg.URL),
+				log.Str("keyword", e.cfg.Keyword),
+				log.Str("expires", e.cfg.Expires),
+			)
+			expired = append(expired, c.expiredFinding(e))
+			continue
+		}
+		active = append(active, e)
+	}
+
+	suppressed := make([]*model.Suppression, 0)
+	if len(active) == 0 {
+		return results, suppressed, expired
+	}
+
+	kept := make([]*model.ScanResult, 0, len(results))
+	for _, result := range results {
+		pb := c.pageBlocks[result]
+		for _, e := range active {
+			if !e.url.MatchString(result.URL) {
+				continue
+			}
+			if counts := suppressHits(result, e, pb); len(counts) > 0 {
+				for _, keyword := range c.cfg.Scanner.Keywords {
+					if counts[keyword] == 0 {
+						continue
+					}
+					suppressed = append(suppressed, &model.Suppression{
+						URL:         result.URL,
+						Keyword:     keyword,
+						Count:       counts[keyword],
+						SnippetHash: e.cfg.SnippetHash,
+						Reason:      e.cfg.Reason,
+						Expires:     e.cfg.Expires,
+					})
+				}
+			}
+		}
+
+		keywords := result.Keywords[:0]
+		for _, keyword := range result.Keywords {
+			if result.KeywordCounts[keyword] > 0 {
+				keywords = append(keywords, keyword)
+			}
+		}
+		result.Keywords = keywords
+
+		if result.TotalCount > 0 || len(result.Sensitive) > 0 {
+			kept = append(kept, result)
+		}
+	}
+	return kept, suppressed, expired
+}
+
+// expiredFinding 为已过期的豁免规则生成附加发现，提醒复核或续期
+func (c *crawler) expiredFinding(e *exemption) *model.Finding {
+	keyword := e.cfg.Keyword
+	if keyword == "" {
+		keyword = c.p.T("finding.exemption.any")
+	}
+	snippetHash := e.cfg.SnippetHash
+	if snippetHash == "" {
+		snippetHash = "-"
+	}
+	return &model.Finding{
+		Type:     model.FindingTypeExemption,
+		Severity: model.SeverityLow,
+		URL:      e.cfg.URL,
+		Key:      e.cfg.Keyword + "|" + e.cfg.SnippetHash,
+		Title:    c.p.T("finding.exemption.title", e.cfg.Expires),
+		Detail:   c.p.T("finding.exemption.detail", keyword, snippetHash, e.cfg.Reason),
+	}
+}
+
+// suppressHits 移除页面中被规则豁免的命中，返回各关键词被豁免的次数
+//
+// 未指定片段哈希的规则豁免关键词在该页面的全部命中；指定片段哈希时只能匹配保留了明细的命中。
+// pb 为页面的区块信息，被豁免的命中同时从区块中移除，不再参与公共区块识别。
+func suppressHits(result *model.ScanResult, e *exemption, pb *pageBlocks) map[string]int {
+	counts := make(map[string]int)
+
+	if e.cfg.SnippetHash == "" {
+		for keyword, count := range result.KeywordCounts {
+			if e.cfg.Keyword == "" || e.cfg.Keyword == keyword {
+				counts[keyword] = count
+			}
+		}
+	}
+
+	hits := result.Hits[:0]
+	for _, hit := range result.Hits {
+		switch {
+		case e.cfg.SnippetHash == "" && counts[hit.Keyword] > 0:
+		case e.cfg.SnippetHash != "" && e.matchHit(hit):
+			counts[hit.Keyword]++
+		default:
+			hits = append(hits, hit)
+			continue
+		}
+		if hit.Hidden {
+			result.HiddenCount--
+		}
+		if pb != nil {
+			pb.removeHit(hit)
+		}
+	}
+	result.Hits = hits
+
+	for keyword, count := range counts {
+		if pb != nil && e.cfg.SnippetHash == "" {
+			pb.removeKeyword(keyword)
+		}
+		result.KeywordCounts[keyword] -= count
+		result.TotalCount -= count
+		if result.KeywordCounts[keyword] <= 0 {
+			delete(result.KeywordCounts, keyword)
+		}
+	}
+	return counts
+}
+
+// snippetHash 计算上下文片段的哈希，用于在豁免规则中指定单个命中
+func snippetHash(snippet string) string {
+	sum := sha256.Sum256([]byte(snippet))
+	return hex.EncodeToString(sum[:])[:16]
+}
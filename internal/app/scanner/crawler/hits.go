@@ -0,0 +1,227 @@
+package crawler
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+const (
+	// maxHitDetails 每个页面最多保留的可见命中明细数，隐藏命中始终保留
+	maxHitDetails = 100
+	// snippetContextRunes 上下文片段中关键词两侧保留的字符数
+	snippetContextRunes = 30
+	// snippetWindowBytes 截取上下文时在原文中向两侧查看的字节数
+	snippetWindowBytes = 400
+	// maxHiddenFindingHits 每条隐藏文本发现中列出的命中数
+	maxHiddenFindingHits = 5
+)
+
+// hiddenNoscript noscript 中的内容在启用脚本的浏览器中不显示
+const hiddenNoscript = "noscript"
+
+var (
+	snippetRawRegexp   = regexp.MustCompile(`(?is)<(script|style)\b[^>]*>.*?</(script|style)\s*>`)
+	snippetTagRegexp   = regexp.MustCompile(`<[^>]*>`)
+	snippetSpaceRegexp = regexp.MustCompile(`\s+`)
+)
+
+// buildHits 生成命中明细，HTML 页面会根据标签位置和样式判断命中是否可见
+func (c *crawler) buildHits(pg *page, result *model.ScanResult) {
+	if pg.streamed || !isHTML(pg.contentType) {
+		text := pg.body
+		if pg.streamed {
+			text = string(pg.raw)
+		}
+		for _, match := range pg.matches {
+			hit := &model.KeywordHit{
+				Keyword:  match.Keyword,
+				Variant:  match.Variant,
+				Distance: match.Distance,
+				Offset:   match.Start,
+				Location: model.HitLocationText,
+			}
+			if match.End <= len(text) {
+				hit.Snippet = snippet(text, match.Start, match.End, false)
+				hit.SnippetHash = snippetHash(hit.Snippet)
+			}
+			addHit(result, hit)
+		}
+		return
+	}
+
+	// 统计公共区块需要所有 HTML 页面的区块，包括没有命中的页面
+	var tokens []*htmlToken
+	var blocks *pageBlocks
+	if c.boilerplateEnabled() {
+		tokens = tokenizeHTML(pg.body)
+		blocks = c.recordBlocks(tokens)
+	}
+	if len(pg.matches) == 0 {
+		return
+	}
+	if tokens == nil {
+		tokens = tokenizeHTML(pg.body)
+	}
+	visibility := newVisibilityEvaluator(tokens)
+
+	next := 0
+	for _, tok := range tokens {
+		for next < len(pg.matches) && pg.matches[next].Start < tok.end {
+			match := pg.matches[next]
+			hit := classifyHit(visibility, tok)
+			hit.Keyword = match.Keyword
+			hit.Variant = match.Variant
+			hit.Distance = match.Distance
+			hit.Offset = match.Start
+			hit.Snippet = snippet(pg.body, match.Start, match.End, hit.Location == model.HitLocationText)
+			hit.SnippetHash = snippetHash(hit.Snippet)
+			stored := addHit(result, hit)
+			if blocks != nil {
+				blocks.addHit(hit, stored)
+			}
+			next++
+		}
+
+		switch tok.typ {
+		case html.StartTagToken:
+			visibility.push(tok.token, false)
+		case html.EndTagToken:
+			visibility.pop(tok.token.Data)
+		}
+	}
+
+	if blocks != nil {
+		c.savePageBlocks(result, blocks)
+	}
+}
+
+// classifyHit 根据命中所在的词法单元确定命中位置及可见性
+func classifyHit(visibility *visibilityEvaluator, tok *htmlToken) *model.KeywordHit {
+	hit := &model.KeywordHit{Element: visibility.path()}
+
+	switch tok.typ {
+	case html.TextToken:
+		tag := ""
+		if current := visibility.current(); current != nil {
+			tag = current.tag
+		}
+		switch tag {
+		case "script":
+			hit.Location = model.HitLocationScript
+		case "style":
+			hit.Location = model.HitLocationStyle
+		default:
+			hit.Location = model.HitLocationText
+			hit.HiddenReason = visibility.textHiddenReason()
+			if hit.HiddenReason == "" && tag == "noscript" {
+				hit.HiddenReason = hiddenNoscript
+			}
+			hit.Hidden = hit.HiddenReason != ""
+		}
+	case html.CommentToken:
+		hit.Location = model.HitLocationComment
+	default:
+		hit.Location = model.HitLocationAttribute
+		if hit.Element != "" {
+			hit.Element += " > "
+		}
+		hit.Element += tok.token.Data
+	}
+
+	return hit
+}
+
+// addHit 记录命中明细，超出上限的可见命中只计数不保留，返回命中明细是否被保留
+func addHit(result *model.ScanResult, hit *model.KeywordHit) bool {
+	if hit.Hidden {
+		result.HiddenCount++
+	} else if len(result.Hits)-result.HiddenCount >= maxHitDetails {
+		return false
+	}
+	result.Hits = append(result.Hits, hit)
+	return true
+}
+
+// snippet 截取命中前后的上下文，正文命中会去除两侧的标签
+func snippet(text string, start, end int, stripTags bool) string {
+	left := text[runeStart(text, start-snippetWindowBytes):start]
+	right := text[end:runeStart(text, end+snippetWindowBytes)]
+
+	if stripTags {
+		left = snippetTagRegexp.ReplaceAllString(snippetRawRegexp.ReplaceAllString(left, " "), " ")
+		right = snippetTagRegexp.ReplaceAllString(snippetRawRegexp.ReplaceAllString(right, " "), " ")
+		// 去除窗口边缘被截断的标签
+		if i := strings.Index(left, ">"); i >= 0 && !strings.Contains(left[:i], "<") {
+			left = left[i+1:]
+		}
+		if i := strings.LastIndex(right, "<"); i >= 0 {
+			right = right[:i]
+		}
+		left = html.UnescapeString(left)
+		right = html.UnescapeString(right)
+	}
+
+	left = strings.TrimLeft(snippetSpaceRegexp.ReplaceAllString(left, " "), " ")
+	right = strings.TrimRight(snippetSpaceRegexp.ReplaceAllString(right, " "), " ")
+
+	if runes := []rune(left); len(runes) > snippetContextRunes {
+		left = "…" + string(runes[len(runes)-snippetContextRunes:])
+	}
+	if runes := []rune(right); len(runes) > snippetContextRunes {
+		right = string(runes[:snippetContextRunes]) + "…"
+	}
+
+	return left + snippetSpaceRegexp.ReplaceAllString(text[start:end], " ") + right
+}
+
+// runeStart 将偏移限制在文本范围内并调整到字符边界
+func runeStart(text string, offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+	if offset >= len(text) {
+		return len(text)
+	}
+	for offset > 0 && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+	return offset
+}
+
+// hiddenTextFindings 为存在隐藏关键词文本的页面生成发现
+func hiddenTextFindings(p *i18n.Printer, results []*model.ScanResult) []*model.Finding {
+	findings := make([]*model.Finding, 0)
+	for _, result := range results {
+		if result.HiddenCount == 0 {
+			continue
+		}
+
+		details := make([]string, 0, maxHiddenFindingHits)
+		for _, hit := range result.Hits {
+			if !hit.Hidden {
+				continue
+			}
+			if len(details) == maxHiddenFindingHits {
+				details = append(details, "…")
+				break
+			}
+			details = append(details, p.T("finding.hidden_text.hit", hit.Keyword, hit.HiddenReason, hit.Element))
+		}
+
+		findings = append(findings, &model.Finding{
+			Type:     model.FindingTypeHiddenText,
+			Severity: model.SeverityHigh,
+			URL:      result.URL,
+			Key:      model.FindingTypeHiddenText,
+			Title:    p.T("finding.hidden_text.title", result.HiddenCount),
+			Detail:   strings.Join(details, p.T("detail.separator")),
+		})
+	}
+	return findings
+}
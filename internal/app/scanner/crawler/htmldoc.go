@@ -0,0 +1,50 @@
+package crawler
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// htmlToken 带原文偏移的 HTML 词法单元
+type htmlToken struct {
+	typ   html.TokenType
+	token html.Token
+	start int    // 在原文中的起始字节偏移
+	end   int    // 在原文中的结束字节偏移（不含）
+	raw   string // 原文内容
+}
+
+// tokenizeHTML 将 HTML 拆分为词法单元，并记录每个单元在原文中的位置
+func tokenizeHTML(body string) []*htmlToken {
+	tokens := make([]*htmlToken, 0)
+	z := html.NewTokenizer(strings.NewReader(body))
+
+	offset := 0
+	for {
+		typ := z.Next()
+		if typ == html.ErrorToken {
+			break
+		}
+		raw := string(z.Raw())
+		tokens = append(tokens, &htmlToken{
+			typ:   typ,
+			token: z.Token(),
+			start: offset,
+			end:   offset + len(raw),
+			raw:   raw,
+		})
+		offset += len(raw)
+	}
+
+	return tokens
+}
+
+// isRawTextTag 判断元素内容是否会被词法分析器作为原始文本整体输出
+func isRawTextTag(tag string) bool {
+	switch tag {
+	case "script", "style", "textarea", "title", "xmp", "iframe", "noembed", "noframes", "noscript", "plaintext":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/app/scanner/detector"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+var defaultLocalExtensions = []string{".html", ".htm", ".xhtml", ".txt", ".md", ".xml", ".json"}
+
+var defaultLocalExcludeDirs = []string{".git", "node_modules"}
+
+// localContentTypes 补充 mime 包未内置的扩展名
+var localContentTypes = map[string]string{
+	".md":       "text/markdown",
+	".markdown": "text/markdown",
+}
+
+// resolveLocalRoot 判断目标是否为本地目录（file:// 或目录路径），返回其绝对路径
+func resolveLocalRoot(target string) (string, bool, error) {
+	dir := target
+	if strings.HasPrefix(target, "file://") {
+		parsed, err := url.Parse(target)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to parse target url: %w", err)
+		}
+		dir = parsed.Path
+	} else if strings.Contains(target, "://") {
+		return "", false, nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		if strings.HasPrefix(target, "file://") {
+			return "", false, fmt.Errorf("failed to stat local target: %w", err)
+		}
+		// 既不是 file:// 也不是已存在的目录，按网站域名处理
+		return "", false, nil
+	}
+	if !info.IsDir() {
+		return "", false, fmt.Errorf("local target is not a directory: %s", dir)
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false, err
+	}
+	return root, true, nil
+}
+
+// fileURL 将本地路径转换为 file:// URL
+func fileURL(filePath string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(filePath)}).String()
+}
+
+func (c *crawler) localExtensions() []string {
+	if c.cfg.Scanner.Local != nil && len(c.cfg.Scanner.Local.Extensions) > 0 {
+		return c.cfg.Scanner.Local.Extensions
+	}
+	return defaultLocalExtensions
+}
+
+// walkLocal 遍历本地目录，所有待扫描文件均作为深度为 0 的起始页面
+func (c *crawler) walkLocal(ctx context.Context, wg *sync.WaitGroup) error {
+	extensions := c.localExtensions()
+	excludeDirs := defaultLocalExcludeDirs
+	if c.cfg.Scanner.Local != nil && len(c.cfg.Scanner.Local.ExcludeDirs) > 0 {
+		excludeDirs = c.cfg.Scanner.Local.ExcludeDirs
+	}
+
+	return filepath.WalkDir(c.localRoot, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			log.Warnc(ctx, "Failed to walk local path", log.Str("path", filePath), log.Err(err))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if d.IsDir() {
+			if filePath != c.localRoot && containsFold(excludeDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !containsFold(extensions, filepath.Ext(filePath)) {
+			return nil
+		}
+
+		wg.Add(1)
+		go c.crawl(ctx, fileURL(filePath), 0, false, wg)
+		return nil
+	})
+}
+
+// fetchLocalFile 读取本地文件，HTML 文件会解析其中的链接
+func (c *crawler) fetchLocalFile(pageURL string) (*page, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filepath.FromSlash(parsed.Path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(parsed.Path))
+	contentType, ok := localContentTypes[ext]
+	if !ok {
+		contentType = mediaType(mime.TypeByExtension(ext))
+	}
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+
+	pg := &page{
+		url:         pageURL,
+		contentType: contentType,
+		fetchedAt:   time.Now(),
+	}
+
+	limit := c.bodyLimit(contentType)
+
+	if !isHTML(contentType) {
+		reader := &bodyReader{reader: file}
+		pg.streamed = true
+		var limited io.Reader = io.LimitReader(reader, limit)
+		var raw *bytes.Buffer
+		if c.evidenceEnabled() {
+			raw = &bytes.Buffer{}
+			limited = io.TeeReader(limited, raw)
+		}
+		sensitive := detector.NewStreamWriter(c.detectors)
+		limited = io.TeeReader(limited, sensitive)
+		pg.matches, err = c.matcher.MatchReader(limited)
+		if err != nil {
+			return nil, err
+		}
+		pg.detections = sensitive.Detections()
+		if raw != nil {
+			pg.raw = raw.Bytes()
+		}
+		pg.size = reader.n
+		pg.truncated = pg.size >= limit && hasMore(reader)
+		return pg, nil
+	}
+
+	bodyBytes, truncated, err := readLimited(file, limit)
+	if err != nil {
+		return nil, err
+	}
+	pg.size = int64(len(bodyBytes))
+	pg.truncated = truncated
+	pg.body = string(bodyBytes)
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(pg.body))
+	if err != nil {
+		return pg, nil
+	}
+
+	pg.links = make([]string, 0)
+	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		if href, exists := s.Attr("href"); exists {
+			pg.links = append(pg.links, href)
+		}
+	})
+
+	return pg, nil
+}
+
+// resolveLocalURL 解析本地页面中的链接，以 / 开头的链接相对于扫描根目录，目录链接指向其中的 index.html；
+// 查询参数与 fragment 只用于浏览器，解析文件路径时去掉，只有查询参数的链接指向当前文件
+func (c *crawler) resolveLocalURL(base *url.URL, ref *url.URL) string {
+	if ref.Scheme != "" || ref.Host != "" {
+		return ""
+	}
+
+	var resolvedPath string
+	switch {
+	case ref.Path == "":
+		resolvedPath = base.Path
+	case strings.HasPrefix(ref.Path, "/"):
+		resolvedPath = path.Join(filepath.ToSlash(c.localRoot), ref.Path)
+	default:
+		resolvedPath = path.Join(path.Dir(base.Path), ref.Path)
+	}
+
+	if strings.HasSuffix(ref.Path, "/") || isDir(filepath.FromSlash(resolvedPath)) {
+		resolvedPath = path.Join(resolvedPath, "index.html")
+	}
+
+	return fileURL(filepath.FromSlash(resolvedPath))
+}
+
+// isLocalScanned 判断本地文件是否为待扫描的类型，链接指向的其他类型文件不读取，也不计入扫描页面数
+func (c *crawler) isLocalScanned(pageURL string) bool {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return false
+	}
+	return containsFold(c.localExtensions(), path.Ext(parsed.Path))
+}
+
+func isFileURL(pageURL string) bool {
+	return strings.HasPrefix(pageURL, "file://")
+}
+
+// isInLocalRoot 判断文件 URL 是否位于扫描根目录内
+func (c *crawler) isInLocalRoot(pageURL string) bool {
+	parsed, err := url.Parse(pageURL)
+	if err != nil || parsed.Scheme != "file" {
+		return false
+	}
+
+	rel, err := filepath.Rel(c.localRoot, filepath.FromSlash(parsed.Path))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func isDir(filePath string) bool {
+	info, err := os.Stat(filePath)
+	return err == nil && info.IsDir()
+}
+
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
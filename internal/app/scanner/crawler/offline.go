@@ -0,0 +1,135 @@
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+	"github.com/gw-gong/key-spy/internal/pkg/warc"
+
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+func (c *crawler) offlineEnabled() bool {
+	return c.cfg.Scanner.Offline != nil && c.cfg.Scanner.Offline.Enabled
+}
+
+// scanOffline 读取 WARC 归档中的响应记录并使用当前关键词配置进行匹配，返回读取的 WARC 文件
+func (c *crawler) scanOffline(ctx context.Context) (string, error) {
+	source, err := warc.ResolveSource(c.cfg.Scanner.Offline.Source)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve offline source: %w", err)
+	}
+
+	log.Infoc(ctx, "Starting offline rescan", log.Str("source", source))
+
+	file, err := os.Open(source)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	reader, err := warc.NewReader(file)
+	if err != nil {
+		return "", err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read warc record: %w", err)
+		}
+		if record.Type != warc.TypeResponse {
+			continue
+		}
+
+		pageURL := c.normalizeURL(record.TargetURI)
+		c.visitedMu.Lock()
+		if c.visited[pageURL] != 0 {
+			c.visitedMu.Unlock()
+			continue
+		}
+		c.visited[pageURL] = visitFollowed
+		c.visitedMu.Unlock()
+
+		depth, _ := strconv.Atoi(record.Field(warcFieldDepth))
+
+		select {
+		case c.semaphore <- struct{}{}:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-c.semaphore }()
+
+			pg, err := c.pageFromRecord(pageURL, record)
+			if err != nil {
+				log.Warnc(ctx, "Failed to parse warc record", log.Str("url", pageURL), log.Err(err))
+				c.addResult(ctx, &model.ScanResult{
+					URL:   pageURL,
+					Depth: depth,
+					Error: err.Error(),
+				})
+				return
+			}
+			c.processPage(ctx, pg, depth)
+		}()
+	}
+
+	return source, nil
+}
+
+// pageFromRecord 将 WARC 响应记录还原为页面
+func (c *crawler) pageFromRecord(pageURL string, record *warc.Record) (*page, error) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(record.Block)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse http response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	pg := &page{
+		url:         pageURL,
+		contentType: mediaType(resp.Header.Get("Content-Type")),
+		recordID:    record.ID,
+		fetchedAt:   record.Date,
+		status:      resp.Proto + " " + resp.Status,
+		header:      resp.Header.Clone(),
+	}
+	if !isHTML(pg.contentType) && !c.isStreamType(pg.contentType) {
+		return pg, nil
+	}
+
+	// 其他工具生成的归档可能保留了压缩编码
+	body, err := newBodyReader(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	bodyBytes, truncated, err := readLimited(body, c.bodyLimit(pg.contentType))
+	if err != nil {
+		return nil, err
+	}
+	pg.body = string(bodyBytes)
+	pg.size = int64(len(bodyBytes))
+	pg.truncated = truncated || record.Field("WARC-Truncated") != ""
+
+	return pg, nil
+}
@@ -0,0 +1,107 @@
+package crawler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+// errSeedsStopped 扫描取消时停止读取 URL 列表
+var errSeedsStopped = errors.New("seed reading stopped")
+
+// crawlSeeds 扫描 URL 列表（seed_urls 与 seed_file），文件按行流式读取，
+// 同时在途的页面数受限，避免一次性为大量 URL 创建 goroutine
+func (c *crawler) crawlSeeds(ctx context.Context, wg *sync.WaitGroup) error {
+	maxPending := c.cfg.Scanner.MaxConcurrent * 2
+	if maxPending <= 0 {
+		maxPending = 1
+	}
+	pending := make(chan struct{}, maxPending)
+
+	count := 0
+	dispatch := func(pageURL string) bool {
+		select {
+		case pending <- struct{}{}:
+		case <-ctx.Done():
+			return false
+		}
+		count++
+		wg.Add(1)
+		go func() {
+			defer func() { <-pending }()
+			c.crawl(ctx, pageURL, 0, true, wg)
+		}()
+		return true
+	}
+
+	for _, seedURL := range c.cfg.Scanner.SeedURLs {
+		if seedURL = strings.TrimSpace(seedURL); seedURL != "" && !dispatch(seedURL) {
+			return ctx.Err()
+		}
+	}
+
+	if c.cfg.Scanner.SeedFile == "-" {
+		// 标准输入只能读取一次，定时扫描的每次执行都使用第一次读取到的列表
+		seedURLs, err := c.stdinSeeds()
+		if err != nil {
+			return err
+		}
+		for _, seedURL := range seedURLs {
+			if !dispatch(seedURL) {
+				return ctx.Err()
+			}
+		}
+	} else if c.cfg.Scanner.SeedFile != "" {
+		file, err := os.Open(c.cfg.Scanner.SeedFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if err := readSeeds(file, dispatch); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return err
+		}
+	}
+
+	if count > 0 {
+		log.Infoc(ctx, "Seed urls dispatched", log.Int("count", count))
+	}
+
+	return nil
+}
+
+// stdinSeeds 读取标准输入中的 URL 列表，只在第一次调用时读取
+func (c *crawler) stdinSeeds() ([]string, error) {
+	c.stdinOnce.Do(func() {
+		c.stdinSeedURLs = make([]string, 0)
+		c.stdinErr = readSeeds(os.Stdin, func(seedURL string) bool {
+			c.stdinSeedURLs = append(c.stdinSeedURLs, seedURL)
+			return true
+		})
+	})
+	return c.stdinSeedURLs, c.stdinErr
+}
+
+// readSeeds 按行读取 URL 列表，跳过空行与 # 开头的注释，handle 返回 false 时停止读取
+func readSeeds(reader io.Reader, handle func(seedURL string) bool) error {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !handle(line) {
+			return errSeedsStopped
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,164 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+// streamFileSuffix 结果流文件的后缀，文件名与报告、WARC 归档共用 {前缀}_{时间}
+const streamFileSuffix = ".stream.jsonl"
+
+// resultBufferSize 结果流的缓冲大小
+const resultBufferSize = 64
+
+func (c *crawler) streamEnabled() bool {
+	return c.cfg.Output != nil && c.cfg.Output.Stream != nil && c.cfg.Output.Stream.Enabled
+}
+
+// openStream 创建本次扫描的结果流并启动消费者，按配置创建结果流文件
+func (c *crawler) openStream(ctx context.Context) error {
+	c.stream = nil
+	c.droppedResults = 0
+	if c.streamEnabled() {
+		if err := os.MkdirAll(c.cfg.Output.Dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+
+		fileName := fmt.Sprintf("%s_%s%s", c.cfg.Output.FilePrefix, c.scanID, streamFileSuffix)
+		file, err := os.OpenFile(filepath.Join(c.cfg.Output.Dir, fileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		c.stream = file
+	}
+
+	c.results = make([]*model.ScanResult, 0)
+	c.resultCh = make(chan *model.ScanResult, resultBufferSize)
+	c.resultsDone = make(chan struct{})
+	go c.consumeResults(ctx)
+	return nil
+}
+
+// closeStream 关闭结果流并等待消费者处理完剩余结果，返回结果流文件路径；调用前所有页面必须已处理完成
+func (c *crawler) closeStream(ctx context.Context) string {
+	close(c.resultCh)
+	<-c.resultsDone
+
+	if dropped := atomic.LoadInt64(&c.droppedResults); dropped > 0 {
+		log.Warnc(ctx, "Result subscribers too slow, results dropped", log.Int("dropped", int(dropped)))
+	}
+	if c.stream == nil {
+		return ""
+	}
+
+	filePath := c.stream.Name()
+	if err := c.stream.Close(); err != nil {
+		log.Errorc(ctx, "Failed to close result stream", log.Err(err))
+	}
+	c.stream = nil
+
+	log.Infoc(ctx, "Result stream written", log.Str("file_path", filePath))
+	return filePath
+}
+
+// Subscribe 订阅扫描过程中逐个产生的页面结果，订阅在多次扫描间持续有效
+func (c *crawler) Subscribe(buffer int) (<-chan *model.ScanResult, func()) {
+	ch := make(chan *model.ScanResult, buffer)
+
+	c.subscribersMu.Lock()
+	c.subscribers[ch] = struct{}{}
+	c.subscribersMu.Unlock()
+
+	cancel := func() {
+		c.subscribersMu.Lock()
+		defer c.subscribersMu.Unlock()
+		if _, ok := c.subscribers[ch]; ok {
+			delete(c.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, cancel
+}
+
+// addResult 将页面结果送入结果流，缓冲已满时等待消费者处理
+func (c *crawler) addResult(ctx context.Context, result *model.ScanResult) {
+	c.resultCh <- result
+}
+
+// consumeResults 按到达顺序消费结果流：追加写入结果流文件、分发给订阅者，并收集用于生成报告
+func (c *crawler) consumeResults(ctx context.Context) {
+	defer close(c.resultsDone)
+
+	for result := range c.resultCh {
+		c.results = append(c.results, result)
+
+		c.subscribersMu.Lock()
+		subscribed := len(c.subscribers) > 0
+		c.subscribersMu.Unlock()
+		if c.stream == nil && !subscribed {
+			continue
+		}
+
+		line, err := json.Marshal(result)
+		if err != nil {
+			log.Warnc(ctx, "Failed to encode result", log.Str("url", result.URL), log.Err(err))
+			continue
+		}
+		if c.stream != nil {
+			c.writeStream(ctx, line)
+		}
+		if subscribed {
+			c.publish(line)
+		}
+	}
+}
+
+// writeStream 追加一行到结果流文件，每行单独写入，扫描中断时文件中保留已处理的页面，也可以用 tail -f 查看进度
+func (c *crawler) writeStream(ctx context.Context, line []byte) {
+	line = append(line, '\n')
+	if _, err := c.stream.Write(line); err != nil {
+		log.Errorc(ctx, "Failed to write result stream, stop streaming", log.Err(err))
+		c.stream.Close()
+		c.stream = nil
+	}
+}
+
+// publish 将结果分发给订阅者，每个订阅者收到各自解码的副本，与收集的结果互不影响；缓冲已满时丢弃，不阻塞爬取
+func (c *crawler) publish(line []byte) {
+	c.subscribersMu.Lock()
+	count := len(c.subscribers)
+	c.subscribersMu.Unlock()
+
+	copies := make([]*model.ScanResult, 0, count)
+	for i := 0; i < count; i++ {
+		copied := &model.ScanResult{}
+		if err := json.Unmarshal(line, copied); err != nil {
+			return
+		}
+		copies = append(copies, copied)
+	}
+
+	// 发送不会阻塞，持有锁避免向已取消的订阅关闭后的通道发送
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+	for ch := range c.subscribers {
+		if len(copies) == 0 {
+			// 解码之后新增的订阅者从下一个结果开始接收
+			break
+		}
+		select {
+		case ch <- copies[0]:
+		default:
+			atomic.AddInt64(&c.droppedResults, 1)
+		}
+		copies = copies[1:]
+	}
+}
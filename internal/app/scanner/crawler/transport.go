@@ -0,0 +1,289 @@
+package crawler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+const (
+	defaultMaxIdleConns      = 100
+	defaultIdleConnTimeoutMs = 90000
+	defaultCertWarnDays      = 30
+)
+
+// newHTTPClient 根据传输层配置创建 HTTP 客户端
+func (c *crawler) newHTTPClient() (*http.Client, error) {
+	transport, err := c.newTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(c.cfg.Scanner.RequestTimeoutMs) * time.Millisecond,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}, nil
+}
+
+func (c *crawler) newTransport() (*http.Transport, error) {
+	transportCfg := c.cfg.Scanner.Transport
+	if transportCfg == nil {
+		transportCfg = &localcfg.TransportConfig{}
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     !transportCfg.DisableHTTP2,
+		MaxIdleConns:          defaultMaxIdleConns,
+		MaxIdleConnsPerHost:   c.cfg.Scanner.MaxConcurrent,
+		MaxConnsPerHost:       transportCfg.MaxConnsPerHost,
+		IdleConnTimeout:       defaultIdleConnTimeoutMs * time.Millisecond,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if transportCfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = transportCfg.MaxIdleConns
+	}
+	if transportCfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = transportCfg.MaxIdleConnsPerHost
+	}
+	if transportCfg.IdleConnTimeoutMs > 0 {
+		transport.IdleConnTimeout = time.Duration(transportCfg.IdleConnTimeoutMs) * time.Millisecond
+	}
+	if transportCfg.DisableHTTP2 {
+		// 非 nil 的空 map 会关闭 HTTP/2 协商
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	if transportCfg.Proxy != nil {
+		proxyFunc, err := newProxyFunc(transportCfg.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = proxyFunc
+	}
+
+	tlsConfig, err := newTLSConfig(transportCfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
+
+// newProxyFunc 根据代理配置生成按请求选择代理的函数
+func newProxyFunc(proxyCfg *localcfg.ProxyConfig) (func(*http.Request) (*url.URL, error), error) {
+	if proxyCfg.Disabled {
+		return nil, nil
+	}
+	// 未配置代理地址时与不配置 proxy 一致，沿用环境变量
+	if proxyCfg.FromEnvironment || (proxyCfg.URL == "" && proxyCfg.HTTPSURL == "") {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	password, err := proxyCfg.Password.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve proxy password: %w", err)
+	}
+
+	parseProxyURL := func(rawURL string) (*url.URL, error) {
+		if rawURL == "" {
+			return nil, nil
+		}
+		proxyURL, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url %q: %w", rawURL, err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme: %s", proxyURL.Scheme)
+		}
+		if proxyCfg.Username != "" {
+			proxyURL.User = url.UserPassword(proxyCfg.Username, password)
+		}
+		return proxyURL, nil
+	}
+
+	httpProxy, err := parseProxyURL(proxyCfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	httpsProxy, err := parseProxyURL(proxyCfg.HTTPSURL)
+	if err != nil {
+		return nil, err
+	}
+	if httpsProxy == nil {
+		httpsProxy = httpProxy
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		if matchNoProxy(req.URL.Hostname(), proxyCfg.NoProxy) {
+			return nil, nil
+		}
+		if req.URL.Scheme == "https" {
+			return httpsProxy, nil
+		}
+		return httpProxy, nil
+	}, nil
+}
+
+func matchNoProxy(host string, noProxy []string) bool {
+	for _, pattern := range noProxy {
+		if pattern == "" {
+			continue
+		}
+		if pattern == "*" || host == pattern {
+			return true
+		}
+		if strings.HasPrefix(pattern, ".") && strings.HasSuffix(host, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// newTLSConfig 根据 TLS 配置创建 tls.Config，未配置时返回 nil 使用默认值
+func newTLSConfig(tlsCfg *localcfg.TLSConfig) (*tls.Config, error) {
+	if tlsCfg == nil {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		InsecureSkipVerify: tlsCfg.InsecureSkipVerify,
+		ServerName:         tlsCfg.ServerName,
+	}
+
+	if len(tlsCfg.CAFiles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		for _, caFile := range tlsCfg.CAFiles {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read ca file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificate found in ca file: %s", caFile)
+			}
+		}
+		config.RootCAs = pool
+	}
+
+	if tlsCfg.CertFile != "" || tlsCfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(tlsCfg.CertFile, tlsCfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	switch tlsCfg.MinVersion {
+	case "":
+	case "1.0":
+		config.MinVersion = tls.VersionTLS10
+	case "1.1":
+		config.MinVersion = tls.VersionTLS11
+	case "1.2":
+		config.MinVersion = tls.VersionTLS12
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported tls min version: %s", tlsCfg.MinVersion)
+	}
+
+	return config, nil
+}
+
+func (c *crawler) certExpiryEnabled() bool {
+	transportCfg := c.cfg.Scanner.Transport
+	return transportCfg != nil && transportCfg.TLS != nil &&
+		transportCfg.TLS.CertExpiry != nil && transportCfg.TLS.CertExpiry.Enabled
+}
+
+// recordCertificate 记录响应主机的叶子证书，每个主机只记录一次
+func (c *crawler) recordCertificate(resp *http.Response) {
+	if !c.certExpiryEnabled() || resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return
+	}
+
+	host := resp.Request.URL.Host
+	c.certsMu.Lock()
+	defer c.certsMu.Unlock()
+	if _, ok := c.certs[host]; !ok {
+		c.certs[host] = resp.TLS.PeerCertificates[0]
+	}
+}
+
+// certExpiryFindings 根据记录的证书生成有效期发现
+func (c *crawler) certExpiryFindings(now time.Time) []*model.Finding {
+	if !c.certExpiryEnabled() {
+		return nil
+	}
+
+	warnDays := c.cfg.Scanner.Transport.TLS.CertExpiry.WarnDays
+	if warnDays <= 0 {
+		warnDays = defaultCertWarnDays
+	}
+
+	c.certsMu.Lock()
+	hosts := make([]string, 0, len(c.certs))
+	for host := range c.certs {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	findings := make([]*model.Finding, 0, len(hosts))
+	for _, host := range hosts {
+		cert := c.certs[host]
+		daysLeft := int(cert.NotAfter.Sub(now).Hours() / 24)
+
+		severity := model.SeverityInfo
+		key := "expires_in"
+		title := c.p.T("finding.cert.expires_in", daysLeft)
+		switch {
+		case !now.Before(cert.NotAfter):
+			severity = model.SeverityHigh
+			key = "expired"
+			title = c.p.T("finding.cert.expired")
+		case daysLeft < warnDays:
+			severity = model.SeverityMedium
+		}
+
+		findings = append(findings, &model.Finding{
+			Type:     model.FindingTypeCertExpiry,
+			Severity: severity,
+			URL:      host,
+			Key:      key,
+			Title:    title,
+			Detail: fmt.Sprintf("subject=%s, issuer=%s, not_after=%s",
+				cert.Subject.CommonName, cert.Issuer.CommonName, c.p.Time(cert.NotAfter)),
+		})
+	}
+	c.certsMu.Unlock()
+
+	return findings
+}
@@ -0,0 +1,491 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// 文本隐藏原因
+const (
+	hiddenDisplayNone = "display:none"
+	hiddenVisibility  = "visibility:hidden"
+	hiddenOpacity     = "opacity:0"
+	hiddenFontSize    = "font-size:0"
+	hiddenOffscreen   = "off-screen"
+	hiddenZeroSize    = "zero-size"
+	hiddenSameColor   = "same-color"
+	hiddenAttribute   = "hidden-attribute"
+)
+
+const (
+	defaultBackground = "#ffffff" // 浏览器默认的画布背景色
+	unknownBackground = "unknown" // 无法识别的背景，不与任何文字颜色相同
+)
+
+// offscreenThresholdPx 定位或缩进小于该值时视为移出屏幕
+const offscreenThresholdPx = -500
+
+var (
+	cssCommentRegexp = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssRuleRegexp    = regexp.MustCompile(`([^{}]+)\{([^{}]*)\}`)
+	cssLengthRegexp  = regexp.MustCompile(`^(-?[0-9]*\.?[0-9]+)(px|pt|em|rem|%|vw|vh)?$`)
+)
+
+// cssRule 简单 CSS 规则，只支持由标签、类、ID 组成的选择器及后代组合
+type cssRule struct {
+	selector [][]*cssCompound // 后代选择器链，最后一项匹配元素本身
+	decls    map[string]string
+}
+
+type cssCompound struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+// elementState 元素及其计算后的可见性相关样式
+type elementState struct {
+	tag     string
+	id      string
+	classes []string
+
+	hiddenReason     string // 自身或祖先导致文本不可见、且子元素无法覆盖的原因
+	visibilityHidden bool   // 继承后的 visibility:hidden，子元素可用 visibility:visible 覆盖
+	fontZero         bool
+	color            string // 继承后的文字颜色
+	background       string // 最近的背景色
+}
+
+// visibilityEvaluator 根据内联样式与 <style> 中的简单规则判断文本是否可见
+type visibilityEvaluator struct {
+	rules []*cssRule
+	stack []*elementState
+}
+
+func newVisibilityEvaluator(tokens []*htmlToken) *visibilityEvaluator {
+	e := &visibilityEvaluator{}
+
+	inStyle := false
+	for _, tok := range tokens {
+		switch tok.typ {
+		case html.StartTagToken:
+			inStyle = tok.token.Data == "style"
+		case html.EndTagToken:
+			inStyle = false
+		case html.TextToken:
+			if inStyle {
+				e.rules = append(e.rules, parseCSSRules(tok.token.Data)...)
+			}
+		}
+	}
+
+	return e
+}
+
+// current 返回当前所在的元素，位于所有元素之外时返回 nil
+func (e *visibilityEvaluator) current() *elementState {
+	if len(e.stack) == 0 {
+		return nil
+	}
+	return e.stack[len(e.stack)-1]
+}
+
+// push 处理开始标签
+func (e *visibilityEvaluator) push(token html.Token, selfClosing bool) {
+	if selfClosing || isVoidElement(token.Data) {
+		return
+	}
+
+	// 未闭合的同名段落类元素视为已隐式闭合
+	if top := e.current(); top != nil && top.tag == token.Data && isAutoClosing(token.Data) {
+		e.stack = e.stack[:len(e.stack)-1]
+	}
+
+	state := &elementState{tag: token.Data}
+	inlineStyle := ""
+	hiddenAttr := false
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "id":
+			state.id = attr.Val
+		case "class":
+			state.classes = strings.Fields(attr.Val)
+		case "style":
+			inlineStyle = attr.Val
+		case "hidden":
+			hiddenAttr = true
+		}
+	}
+
+	parent := e.current()
+	if parent != nil {
+		state.hiddenReason = parent.hiddenReason
+		state.visibilityHidden = parent.visibilityHidden
+		state.fontZero = parent.fontZero
+		state.color = parent.color
+		state.background = parent.background
+	}
+
+	decls := make(map[string]string)
+	for _, rule := range e.rules {
+		if e.matchSelector(rule.selector, state) {
+			for prop, value := range rule.decls {
+				decls[prop] = value
+			}
+		}
+	}
+	for prop, value := range parseDeclarations(inlineStyle) {
+		decls[prop] = value
+	}
+
+	e.apply(state, decls, hiddenAttr)
+	e.stack = append(e.stack, state)
+}
+
+// pop 处理结束标签，弹出到最近的同名元素
+func (e *visibilityEvaluator) pop(tag string) {
+	for i := len(e.stack) - 1; i >= 0; i-- {
+		if e.stack[i].tag == tag {
+			e.stack = e.stack[:i]
+			return
+		}
+	}
+}
+
+func (e *visibilityEvaluator) apply(state *elementState, decls map[string]string, hiddenAttr bool) {
+	setHidden := func(reason string) {
+		if state.hiddenReason == "" {
+			state.hiddenReason = reason
+		}
+	}
+
+	if hiddenAttr {
+		setHidden(hiddenAttribute)
+	}
+	if decls["display"] == "none" {
+		setHidden(hiddenDisplayNone)
+	}
+	switch decls["visibility"] {
+	case "hidden", "collapse":
+		state.visibilityHidden = true
+	case "visible":
+		state.visibilityHidden = false
+	}
+	if v, ok := decls["opacity"]; ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f <= 0 {
+			setHidden(hiddenOpacity)
+		}
+	}
+
+	if v, ok := decls["font-size"]; ok {
+		size, ok := cssLength(v)
+		state.fontZero = ok && size <= 0
+	}
+
+	if pos := decls["position"]; pos == "absolute" || pos == "fixed" {
+		for _, prop := range []string{"left", "top", "right", "margin-left", "margin-top"} {
+			if length, ok := cssLength(decls[prop]); ok && length <= offscreenThresholdPx {
+				setHidden(hiddenOffscreen)
+				break
+			}
+		}
+	}
+	if length, ok := cssLength(decls["text-indent"]); ok && length <= offscreenThresholdPx {
+		setHidden(hiddenOffscreen)
+	}
+
+	if overflow := decls["overflow"]; overflow == "hidden" {
+		height, hasHeight := cssLength(decls["height"])
+		width, hasWidth := cssLength(decls["width"])
+		if (hasHeight && height <= 0) || (hasWidth && width <= 0) {
+			setHidden(hiddenZeroSize)
+		}
+	}
+
+	if v, ok := decls["color"]; ok {
+		state.color = normalizeColor(v)
+	}
+	if v, ok := decls["background-color"]; ok {
+		setBackground(state, []string{v})
+	} else if v, ok := decls["background"]; ok {
+		// background 简写只取其中的颜色值
+		setBackground(state, strings.Fields(v))
+	}
+}
+
+// setBackground 根据背景声明更新背景色：透明时沿用父元素背景，无法识别（如图片、渐变）时不再判断同色
+func setBackground(state *elementState, parts []string) {
+	transparent := false
+	for _, part := range parts {
+		if color := normalizeColor(part); color != "" {
+			state.background = color
+			return
+		}
+		if isTransparent(part) {
+			transparent = true
+		}
+	}
+	if !transparent {
+		state.background = unknownBackground
+	}
+}
+
+// textHiddenReason 返回当前位置文本的隐藏原因，可见时返回空字符串
+func (e *visibilityEvaluator) textHiddenReason() string {
+	state := e.current()
+	if state == nil {
+		return ""
+	}
+	if state.hiddenReason != "" {
+		return state.hiddenReason
+	}
+	if state.visibilityHidden {
+		return hiddenVisibility
+	}
+	if state.fontZero {
+		return hiddenFontSize
+	}
+	// 未设置背景时按浏览器默认的白色画布判断
+	background := state.background
+	if background == "" {
+		background = defaultBackground
+	}
+	if state.color != "" && state.color == background {
+		return hiddenSameColor
+	}
+	return ""
+}
+
+// path 返回当前元素路径，用于报告定位
+func (e *visibilityEvaluator) path() string {
+	const maxParts = 4
+	start := 0
+	if len(e.stack) > maxParts {
+		start = len(e.stack) - maxParts
+	}
+
+	parts := make([]string, 0, maxParts)
+	for _, state := range e.stack[start:] {
+		part := state.tag
+		if state.id != "" {
+			part += "#" + state.id
+		}
+		for _, class := range state.classes {
+			part += "." + class
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " > ")
+}
+
+func (e *visibilityEvaluator) matchSelector(selector [][]*cssCompound, state *elementState) bool {
+	if len(selector) == 0 || !matchCompound(selector[len(selector)-1], state) {
+		return false
+	}
+
+	// 其余部分依次匹配祖先元素
+	remaining := len(selector) - 2
+	for i := len(e.stack) - 1; i >= 0 && remaining >= 0; i-- {
+		if matchCompound(selector[remaining], e.stack[i]) {
+			remaining--
+		}
+	}
+	return remaining < 0
+}
+
+func matchCompound(compounds []*cssCompound, state *elementState) bool {
+	for _, compound := range compounds {
+		if compound.tag != "" && compound.tag != "*" && compound.tag != state.tag {
+			continue
+		}
+		if compound.id != "" && compound.id != state.id {
+			continue
+		}
+		matched := true
+		for _, class := range compound.classes {
+			if !slices.Contains(state.classes, class) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCSSRules 解析 <style> 中的规则，忽略 @ 规则及包含不支持语法的选择器
+func parseCSSRules(css string) []*cssRule {
+	css = cssCommentRegexp.ReplaceAllString(css, "")
+
+	rules := make([]*cssRule, 0)
+	for _, m := range cssRuleRegexp.FindAllStringSubmatch(css, -1) {
+		decls := parseDeclarations(m[2])
+		if len(decls) == 0 {
+			continue
+		}
+		for _, selectorText := range strings.Split(m[1], ",") {
+			if selector := parseSelector(selectorText); selector != nil {
+				rules = append(rules, &cssRule{selector: selector, decls: decls})
+			}
+		}
+	}
+	return rules
+}
+
+// parseSelector 将后代选择器拆分为复合选择器链，每一层只有一个复合选择器
+func parseSelector(text string) [][]*cssCompound {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.HasPrefix(text, "@") || strings.ContainsAny(text, "[]:>+~()") {
+		return nil
+	}
+
+	selector := make([][]*cssCompound, 0)
+	for _, part := range strings.Fields(text) {
+		compound := &cssCompound{}
+		for _, piece := range splitCompound(part) {
+			switch {
+			case strings.HasPrefix(piece, "#"):
+				compound.id = piece[1:]
+			case strings.HasPrefix(piece, "."):
+				compound.classes = append(compound.classes, piece[1:])
+			default:
+				compound.tag = strings.ToLower(piece)
+			}
+		}
+		selector = append(selector, []*cssCompound{compound})
+	}
+	return selector
+}
+
+// splitCompound 将 div.a#b 拆分为 div、.a、#b
+func splitCompound(part string) []string {
+	pieces := make([]string, 0)
+	start := 0
+	for i := 1; i < len(part); i++ {
+		if part[i] == '.' || part[i] == '#' {
+			pieces = append(pieces, part[start:i])
+			start = i
+		}
+	}
+	return append(pieces, part[start:])
+}
+
+// parseDeclarations 解析 CSS 声明，属性名与值均转为小写并去除 !important
+func parseDeclarations(text string) map[string]string {
+	decls := make(map[string]string)
+	for _, decl := range strings.Split(text, ";") {
+		prop, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		prop = strings.ToLower(strings.TrimSpace(prop))
+		value = strings.ToLower(strings.TrimSpace(value))
+		value = strings.TrimSpace(strings.TrimSuffix(value, "!important"))
+		if prop != "" && value != "" {
+			decls[prop] = value
+		}
+	}
+	return decls
+}
+
+// cssLength 解析长度值，em/rem 按 16px 换算，其他单位只保留数值
+func cssLength(value string) (float64, bool) {
+	m := cssLengthRegexp.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	if m[2] == "em" || m[2] == "rem" {
+		f *= 16
+	}
+	return f, true
+}
+
+var namedColors = map[string]string{
+	"white":  "#ffffff",
+	"black":  "#000000",
+	"red":    "#ff0000",
+	"green":  "#008000",
+	"blue":   "#0000ff",
+	"yellow": "#ffff00",
+	"gray":   "#808080",
+	"grey":   "#808080",
+	"silver": "#c0c0c0",
+	"orange": "#ffa500",
+	"purple": "#800080",
+	"navy":   "#000080",
+}
+
+var rgbColorRegexp = regexp.MustCompile(`^rgba?\(\s*(\d+)\s*,\s*(\d+)\s*,\s*(\d+)\s*(?:,\s*([0-9.]+)\s*)?\)$`)
+
+// isTransparent 判断背景值是否透明
+func isTransparent(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "transparent" || value == "none" {
+		return true
+	}
+	if m := rgbColorRegexp.FindStringSubmatch(value); m != nil && m[4] != "" {
+		alpha, err := strconv.ParseFloat(m[4], 64)
+		return err == nil && alpha == 0
+	}
+	return false
+}
+
+// normalizeColor 将颜色统一为 #rrggbb 形式，无法识别或透明时返回空字符串
+func normalizeColor(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if color, ok := namedColors[value]; ok {
+		return color
+	}
+
+	if strings.HasPrefix(value, "#") {
+		hex := value[1:]
+		switch len(hex) {
+		case 3:
+			return fmt.Sprintf("#%c%c%c%c%c%c", hex[0], hex[0], hex[1], hex[1], hex[2], hex[2])
+		case 6:
+			return value
+		}
+		return ""
+	}
+
+	if m := rgbColorRegexp.FindStringSubmatch(value); m != nil {
+		if m[4] != "" {
+			if alpha, err := strconv.ParseFloat(m[4], 64); err == nil && alpha == 0 {
+				return ""
+			}
+		}
+		r, _ := strconv.Atoi(m[1])
+		g, _ := strconv.Atoi(m[2])
+		b, _ := strconv.Atoi(m[3])
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+
+	return ""
+}
+
+func isVoidElement(tag string) bool {
+	switch tag {
+	case "area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "param", "source", "track", "wbr":
+		return true
+	}
+	return false
+}
+
+func isAutoClosing(tag string) bool {
+	switch tag {
+	case "p", "li", "dt", "dd", "tr", "td", "th", "option":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package detector
+
+import (
+	"io"
+)
+
+// Detection 表示一次敏感数据命中
+type Detection struct {
+	Detector string // 检测器名称
+	Start    int    // 命中内容在原文中的起始字节偏移
+	End      int    // 命中内容在原文中的结束字节偏移（不含）
+	Masked   string // 脱敏后的值
+}
+
+// Detector 敏感数据检测器接口
+type Detector interface {
+	// Name 返回检测器名称，与配置中的名称一致
+	Name() string
+	// Severity 返回命中的严重程度
+	Severity() string
+	// Detect 在文本中查找敏感数据，按出现位置排序
+	Detect(text string) []*Detection
+}
+
+// StreamWriter 在流式读取的内容中查找敏感数据，写入的内容不会完整保留在内存中
+type StreamWriter interface {
+	io.Writer
+	// Detections 结束写入并返回全部命中
+	Detections() []*Detection
+}
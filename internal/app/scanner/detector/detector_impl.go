@@ -0,0 +1,166 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// 内置检测器名称
+const (
+	NameCNIDCard   = "cn_id_card"
+	NameCNMobile   = "cn_mobile"
+	NameBankCard   = "bank_card"
+	NameEmail      = "email"
+	NameAWSKey     = "aws_key"
+	NamePrivateKey = "private_key"
+	NameJWT        = "jwt"
+)
+
+// regexDetector 基于正则表达式的检测器，可选校验命中内容并定义脱敏方式
+type regexDetector struct {
+	name     string
+	severity string
+	re       *regexp.Regexp
+	validate func(value string) bool // 为空时不校验
+	mask     func(value string) string
+}
+
+var registry = map[string]func() Detector{
+	NameCNIDCard: func() Detector {
+		return &regexDetector{
+			name:     NameCNIDCard,
+			severity: model.SeverityHigh,
+			re:       regexp.MustCompile(`\b[1-9]\d{5}(?:18|19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]\b`),
+			validate: validIDCard,
+			mask:     maskKeep(6, 4),
+		}
+	},
+	NameCNMobile: func() Detector {
+		return &regexDetector{
+			name:     NameCNMobile,
+			severity: model.SeverityMedium,
+			re:       regexp.MustCompile(`\b1[3-9]\d{9}\b`),
+			mask:     maskKeep(3, 4),
+		}
+	},
+	NameBankCard: func() Detector {
+		return &regexDetector{
+			name:     NameBankCard,
+			severity: model.SeverityHigh,
+			re:       regexp.MustCompile(`\b[3-6]\d{3}(?:[ -]?\d{4}){2}[ -]?\d{4,7}\b`),
+			validate: validBankCard,
+			mask:     func(value string) string { return maskKeep(6, 4)(digitsOnly(value)) },
+		}
+	},
+	NameEmail: func() Detector {
+		return &regexDetector{
+			name:     NameEmail,
+			severity: model.SeverityLow,
+			re:       regexp.MustCompile(`\b[A-Za-z0-9._%+-]{1,64}@(?:[A-Za-z0-9-]{1,63}\.){1,8}[A-Za-z]{2,24}\b`),
+			mask:     maskEmail,
+		}
+	},
+	NameAWSKey: func() Detector {
+		return &regexDetector{
+			name:     NameAWSKey,
+			severity: model.SeverityCritical,
+			re:       regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b|(?i:aws_?secret_?access_?key)["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`),
+			mask:     maskKeep(4, 4),
+		}
+	},
+	NamePrivateKey: func() Detector {
+		return &regexDetector{
+			name:     NamePrivateKey,
+			severity: model.SeverityCritical,
+			re:       regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----`),
+			// 只保留密钥头，不包含密钥内容
+			mask: func(value string) string { return value },
+		}
+	},
+	NameJWT: func() Detector {
+		return &regexDetector{
+			name:     NameJWT,
+			severity: model.SeverityHigh,
+			re:       regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,1000}\.eyJ[A-Za-z0-9_-]{8,1000}\.[A-Za-z0-9_-]{8,1000}`),
+			validate: validJWT,
+			mask:     maskJWT,
+		}
+	},
+}
+
+// maxDetectionBytes 单次命中的最大字节数，用于流式检测时保留的尾部窗口
+const maxDetectionBytes = 4096
+
+// NewDetectors 按名称创建检测器，名称未知时返回错误
+func NewDetectors(names []string) ([]Detector, error) {
+	detectors := make([]Detector, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		newDetector, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown detector: %s", name)
+		}
+		detectors = append(detectors, newDetector())
+	}
+	return detectors, nil
+}
+
+// DetectAll 使用多个检测器查找敏感数据，按出现位置排序
+func DetectAll(detectors []Detector, text string) []*Detection {
+	detections := make([]*Detection, 0)
+	for _, d := range detectors {
+		detections = append(detections, d.Detect(text)...)
+	}
+	sortDetections(detections)
+	return detections
+}
+
+func (d *regexDetector) Name() string {
+	return d.name
+}
+
+func (d *regexDetector) Severity() string {
+	return d.severity
+}
+
+func (d *regexDetector) Detect(text string) []*Detection {
+	detections := make([]*Detection, 0)
+	for _, loc := range d.re.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		// 取第一个匹配到的分组，没有分组时使用整个命中
+		for i := 2; i < len(loc); i += 2 {
+			if loc[i] >= 0 {
+				start, end = loc[i], loc[i+1]
+				break
+			}
+		}
+
+		value := text[start:end]
+		if d.validate != nil && !d.validate(value) {
+			continue
+		}
+		detections = append(detections, &Detection{
+			Detector: d.name,
+			Start:    start,
+			End:      end,
+			Masked:   d.mask(value),
+		})
+	}
+	return detections
+}
+
+func sortDetections(detections []*Detection) {
+	sort.SliceStable(detections, func(i, j int) bool {
+		return detections[i].Start < detections[j].Start
+	})
+}
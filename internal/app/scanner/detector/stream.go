@@ -0,0 +1,85 @@
+package detector
+
+const (
+	streamChunkSize = 32 * 1024
+	// maxWordCarry 截断缓冲区时为保留完整单词最多向前回退的字节数
+	maxWordCarry = 256
+)
+
+// streamWriter 分块检测写入的内容，保留不小于单次命中最大长度的尾部窗口以处理跨块的命中
+type streamWriter struct {
+	detectors  []Detector
+	buf        []byte
+	bufStart   int // buf[0] 在原文中的偏移
+	scanned    int // 起点在此之前的命中已在之前的分块中处理
+	detections []*Detection
+}
+
+// NewStreamWriter 创建流式检测器，没有检测器时写入的内容直接丢弃
+func NewStreamWriter(detectors []Detector) StreamWriter {
+	return &streamWriter{
+		detectors:  detectors,
+		detections: make([]*Detection, 0),
+	}
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	if len(w.detectors) == 0 {
+		return len(p), nil
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= streamChunkSize+maxDetectionBytes {
+		w.scan(false)
+	}
+	return len(p), nil
+}
+
+func (w *streamWriter) Detections() []*Detection {
+	if len(w.detectors) > 0 {
+		w.scan(true)
+	}
+	sortDetections(w.detections)
+	return w.detections
+}
+
+// scan 检测当前缓冲区，起点在边界之前的命中已完整落在缓冲区中
+func (w *streamWriter) scan(final bool) {
+	boundary := len(w.buf)
+	if !final {
+		boundary -= maxDetectionBytes
+	}
+
+	text := string(w.buf)
+	for _, d := range w.detectors {
+		for _, detection := range d.Detect(text) {
+			if detection.Start >= boundary {
+				break
+			}
+			detection.Start += w.bufStart
+			detection.End += w.bufStart
+			if detection.Start < w.scanned {
+				continue
+			}
+			w.detections = append(w.detections, detection)
+		}
+	}
+
+	if final {
+		w.buf = nil
+		return
+	}
+	w.scanned = w.bufStart + boundary
+
+	// 从单词开头保留，避免号码片段被当作完整号码
+	cut := boundary
+	for cut > 0 && boundary-cut < maxWordCarry && isWordByte(w.buf[cut-1]) {
+		cut--
+	}
+	w.buf = append(w.buf[:0], w.buf[cut:]...)
+	w.bufStart += cut
+}
+
+func isWordByte(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b == '_'
+}
@@ -0,0 +1,106 @@
+package detector
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+)
+
+// validIDCard 校验 18 位居民身份证号码的校验码（GB 11643-1999）
+func validIDCard(value string) bool {
+	if len(value) != 18 {
+		return false
+	}
+
+	weights := []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	const checkCodes = "10X98765432"
+
+	sum := 0
+	for i, w := range weights {
+		c := value[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		sum += int(c-'0') * w
+	}
+	return checkCodes[sum%11] == strings.ToUpper(value[17:])[0]
+}
+
+// validBankCard 使用 Luhn 算法校验 16 到 19 位银行卡号，排除恰好通过校验的身份证号
+func validBankCard(value string) bool {
+	digits := digitsOnly(value)
+	if len(digits) < 16 || len(digits) > 19 || validIDCard(digits) {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validJWT 校验 JWT 头部是否为包含 alg 字段的 JSON
+func validJWT(value string) bool {
+	header, _, _ := strings.Cut(value, ".")
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(header, "="))
+	if err != nil {
+		return false
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(decoded, &fields); err != nil {
+		return false
+	}
+	_, ok := fields["alg"]
+	return ok
+}
+
+func digitsOnly(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+// maskKeep 保留开头 head 个和结尾 tail 个字符，其余以 * 代替
+func maskKeep(head, tail int) func(value string) string {
+	return func(value string) string {
+		n := utf8.RuneCountInString(value)
+		if n <= head+tail {
+			return strings.Repeat("*", n)
+		}
+		runes := []rune(value)
+		return string(runes[:head]) + strings.Repeat("*", n-head-tail) + string(runes[n-tail:])
+	}
+}
+
+// maskEmail 只保留用户名首字符与域名
+func maskEmail(value string) string {
+	user, domain, ok := strings.Cut(value, "@")
+	if !ok || user == "" {
+		return value
+	}
+	return user[:1] + "***@" + domain
+}
+
+// maskJWT 只保留头部前若干字符，签名与载荷不出现在报告中
+func maskJWT(value string) string {
+	const keep = 10
+	if len(value) <= keep {
+		return value
+	}
+	return value[:keep] + "…"
+}
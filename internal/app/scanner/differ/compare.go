@@ -0,0 +1,154 @@
+package differ
+
+import (
+	"maps"
+	"regexp"
+	"sort"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// digitsRe 附加发现标题中的数量每次扫描都可能变化，按标题比较时忽略
+var digitsRe = regexp.MustCompile(`\d+`)
+
+// Compare 按页面与关键词比较两次扫描，并标记本次各页面结果的变化
+func Compare(previous, current *model.ScanReport) *model.ScanDiff {
+	diff := &model.ScanDiff{
+		PreviousStartTime: previous.StartTime,
+		NewHits:           make([]*model.HitChange, 0),
+		ResolvedHits:      make([]*model.HitChange, 0),
+		ChangedHits:       make([]*model.HitChange, 0),
+		NewFindings:       make([]*model.Finding, 0),
+	}
+
+	previousPages := pageCounts(previous)
+	currentCounts := keywordCounts(current)
+
+	for _, result := range current.Results {
+		before, seen := previousPages[result.URL]
+		result.Change = model.ChangePersisting
+		if !seen {
+			before = &pageCount{}
+			result.Change = model.ChangeNew
+		}
+		for _, keyword := range sortedKeys(result.KeywordCounts) {
+			count := result.KeywordCounts[keyword]
+			change := &model.HitChange{URL: result.URL, Keyword: keyword, Previous: before.keywords[keyword], Current: count}
+			switch {
+			case change.Previous == 0:
+				diff.NewHits = append(diff.NewHits, change)
+				if seen {
+					result.Change = model.ChangeChanged
+				}
+			case change.Previous != count:
+				diff.ChangedHits = append(diff.ChangedHits, change)
+				diff.PersistingCount++
+				result.Change = model.ChangeChanged
+			default:
+				diff.PersistingCount++
+			}
+		}
+		if seen && result.Change == model.ChangePersisting {
+			for keyword := range before.keywords {
+				if result.KeywordCounts[keyword] == 0 {
+					result.Change = model.ChangeChanged
+					break
+				}
+			}
+		}
+		// 敏感数据与关键词一样按页面比较，各检测器的命中数不变时页面视为未变化；新出现的敏感数据由附加发现列出
+		if seen && result.Change == model.ChangePersisting && !maps.Equal(before.sensitive, sensitiveCounts(result)) {
+			result.Change = model.ChangeChanged
+		}
+	}
+
+	// 只有本次成功抓取的页面才能判断命中是否已消失，抓取失败或未访问到的页面不计入
+	for _, result := range previous.Results {
+		if !current.FetchedURLs[result.URL] {
+			continue
+		}
+		after := currentCounts[result.URL]
+		for _, keyword := range sortedKeys(result.KeywordCounts) {
+			if after[keyword] == 0 {
+				diff.ResolvedHits = append(diff.ResolvedHits, &model.HitChange{
+					URL:      result.URL,
+					Keyword:  keyword,
+					Previous: result.KeywordCounts[keyword],
+				})
+			}
+		}
+	}
+
+	previousFindings := make(map[string]bool)
+	for _, finding := range previous.Findings {
+		previousFindings[findingKey(finding)] = true
+	}
+	for _, finding := range current.Findings {
+		// 早期扫描的发现没有 Key，只能按标题比较
+		if !previousFindings[findingKey(finding)] && !previousFindings[titleKey(finding)] {
+			diff.NewFindings = append(diff.NewFindings, finding)
+		}
+	}
+
+	return diff
+}
+
+// pageCount 页面的关键词出现次数与各检测器的敏感数据命中数
+type pageCount struct {
+	keywords  map[string]int
+	sensitive map[string]int
+}
+
+// pageCounts 按页面汇总上一次扫描的命中，包括只检出敏感数据的页面
+func pageCounts(report *model.ScanReport) map[string]*pageCount {
+	counts := make(map[string]*pageCount)
+	for _, result := range report.Results {
+		sensitive := sensitiveCounts(result)
+		if len(result.KeywordCounts) > 0 || len(sensitive) > 0 {
+			counts[result.URL] = &pageCount{keywords: result.KeywordCounts, sensitive: sensitive}
+		}
+	}
+	return counts
+}
+
+// sensitiveCounts 按检测器汇总页面的敏感数据命中数
+func sensitiveCounts(result *model.ScanResult) map[string]int {
+	counts := make(map[string]int)
+	for _, hit := range result.Sensitive {
+		counts[hit.Detector]++
+	}
+	return counts
+}
+
+// keywordCounts 按页面汇总关键词出现次数
+func keywordCounts(report *model.ScanReport) map[string]map[string]int {
+	counts := make(map[string]map[string]int)
+	for _, result := range report.Results {
+		if len(result.KeywordCounts) > 0 {
+			counts[result.URL] = result.KeywordCounts
+		}
+	}
+	return counts
+}
+
+// findingKey 附加发现的比较键，与报告语言无关；严重程度升级视为新的发现
+func findingKey(finding *model.Finding) string {
+	if finding.Key == "" {
+		return titleKey(finding)
+	}
+	return finding.Type + "|" + finding.Severity + "|" + finding.URL + "|" + finding.Key
+}
+
+// titleKey 按标题比较的键，标题随报告语言变化，只用于没有 Key 的发现
+func titleKey(finding *model.Finding) string {
+	return finding.Type + "|" + finding.Severity + "|" + finding.URL + "||" + digitsRe.ReplaceAllString(finding.Title, "#")
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
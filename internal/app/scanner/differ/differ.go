@@ -0,0 +1,13 @@
+package differ
+
+import (
+	"context"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// Differ 扫描结果比较器接口
+type Differ interface {
+	// Apply 与扫描历史中同一目标的上一次扫描比较，将变化写入 report.Diff
+	Apply(ctx context.Context, report *model.ScanReport) error
+}
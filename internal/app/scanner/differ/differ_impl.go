@@ -0,0 +1,65 @@
+package differ
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gw-gong/key-spy/internal/app/scanner/history"
+	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+// baselineModes 可作为比较基线的扫描模式，离线重扫使用的是历史归档，不作为基线
+var baselineModes = []string{model.ScanModeOnline, model.ScanModeLocal}
+
+type differ struct {
+	cfg     *localcfg.Config
+	history history.Store
+}
+
+// NewDiffer 创建扫描结果比较器
+func NewDiffer(cfg *localcfg.Config, history history.Store) Differ {
+	return &differ{
+		cfg:     cfg,
+		history: history,
+	}
+}
+
+func (d *differ) Apply(ctx context.Context, report *model.ScanReport) error {
+	if d.cfg.Output.Diff == nil || !d.cfg.Output.Diff.Enabled {
+		return nil
+	}
+	if d.cfg.Output.History == nil || !d.cfg.Output.History.Enabled {
+		return errors.New("output.diff requires output.history to be enabled")
+	}
+
+	records, err := d.history.ListScans(ctx, &history.Query{
+		TargetURL: report.TargetURL,
+		Modes:     baselineModes,
+		Limit:     1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find previous scan: %w", err)
+	}
+	if len(records) == 0 {
+		log.Infoc(ctx, "No previous scan to compare with", log.Str("target_url", report.TargetURL))
+		return nil
+	}
+
+	previous, err := d.history.GetScan(ctx, records[0].ID)
+	if err != nil {
+		return fmt.Errorf("failed to load previous scan: %w", err)
+	}
+	report.Diff = Compare(previous, report)
+	log.Infoc(ctx, "Compared with previous scan",
+		log.Str("previous_scan_id", records[0].ID),
+		log.Int("new_hits", len(report.Diff.NewHits)),
+		log.Int("resolved_hits", len(report.Diff.ResolvedHits)),
+		log.Int("changed_hits", len(report.Diff.ChangedHits)),
+		log.Int("new_findings", len(report.Diff.NewFindings)),
+	)
+	return nil
+}
@@ -0,0 +1,63 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// Store 扫描历史存储接口
+type Store interface {
+	// Save 保存一次扫描的完整结果，返回扫描 ID；未启用历史记录时不保存并返回空 ID
+	Save(ctx context.Context, report *model.ScanReport) (id string, err error)
+	// ListScans 按条件查询扫描记录，按开始时间倒序排列
+	ListScans(ctx context.Context, query *Query) ([]*Record, error)
+	// GetScan 读取某次扫描的完整报告
+	GetScan(ctx context.Context, id string) (*model.ScanReport, error)
+	// QueryHits 按条件查询历史命中，按扫描时间倒序排列
+	QueryHits(ctx context.Context, query *Query) ([]*HitRecord, error)
+	// Delete 删除指定扫描的索引记录与完整结果，返回释放的字节数
+	Delete(ctx context.Context, ids []string) (freed int64, err error)
+}
+
+// Query 查询条件，零值字段表示不限
+type Query struct {
+	TargetURL string    // 目标网站
+	Since     time.Time // 扫描开始时间下限（含）
+	Until     time.Time // 扫描开始时间上限（不含）
+	URL       string    // 页面 URL，* 匹配任意字符
+	Keyword   string    // 命中的关键词
+	Modes     []string  // 扫描模式
+	Limit     int       // 最多返回的记录数
+}
+
+// Record 索引中的一次扫描记录
+type Record struct {
+	ID              string         `json:"id"`                         // 扫描 ID
+	TargetURL       string         `json:"target_url"`                 // 目标网站
+	ScanMode        string         `json:"scan_mode,omitempty"`        // 扫描模式
+	StartTime       time.Time      `json:"start_time"`                 // 开始时间
+	EndTime         time.Time      `json:"end_time"`                   // 结束时间
+	Duration        string         `json:"duration"`                   // 耗时
+	Keywords        []string       `json:"keywords"`                   // 搜索的关键词列表
+	MatchedKeywords []string       `json:"matched_keywords,omitempty"` // 有命中的关键词
+	TotalPages      int            `json:"total_pages"`                // 扫描的总页面数
+	MatchPages      int            `json:"match_pages"`                // 匹配的页面数
+	HitCount        int            `json:"hit_count"`                  // 关键词总出现次数
+	KeywordCounts   map[string]int `json:"keyword_counts,omitempty"`   // 各关键词出现次数
+	ErrorCount      int            `json:"error_count"`                // 错误数
+	FindingCount    int            `json:"finding_count"`              // 附加发现数
+	File            string         `json:"file"`                       // 完整结果文件，相对历史目录
+}
+
+// HitRecord 某次扫描中一个页面上一个关键词的命中
+type HitRecord struct {
+	ScanID    string              `json:"scan_id"`        // 扫描 ID
+	TargetURL string              `json:"target_url"`     // 目标网站
+	StartTime time.Time           `json:"start_time"`     // 扫描开始时间
+	URL       string              `json:"url"`            // 页面 URL
+	Keyword   string              `json:"keyword"`        // 关键词
+	Count     int                 `json:"count"`          // 出现次数
+	Hits      []*model.KeywordHit `json:"hits,omitempty"` // 命中明细
+}
@@ -0,0 +1,423 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+const (
+	indexFileName = "index.jsonl" // 扫描索引，每行一条记录
+	scansDirName  = "scans"       // 每次扫描的完整结果
+)
+
+// reportTimeLayout 扫描报告中的时间格式
+const reportTimeLayout = "2006-01-02 15:04:05"
+
+type store struct {
+	cfg *localcfg.Config
+	mu  sync.Mutex
+}
+
+// NewStore 创建扫描历史存储，数据保存在 output.history.dir（默认 {output.dir}/history）
+func NewStore(cfg *localcfg.Config) Store {
+	return &store{
+		cfg: cfg,
+	}
+}
+
+func (s *store) enabled() bool {
+	return s.cfg.Output.History != nil && s.cfg.Output.History.Enabled
+}
+
+func (s *store) dir() string {
+	if s.cfg.Output.History != nil && s.cfg.Output.History.Dir != "" {
+		return s.cfg.Output.History.Dir
+	}
+	return filepath.Join(s.cfg.Output.Dir, "history")
+}
+
+func (s *store) Save(ctx context.Context, report *model.ScanReport) (string, error) {
+	if !s.enabled() {
+		return "", nil
+	}
+
+	record := newRecord(report)
+	scansDir := filepath.Join(s.dir(), scansDirName)
+	if err := os.MkdirAll(scansDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	// 对比与走势由查询时的历史计算，不随扫描结果保存
+	saved := *report
+	saved.Diff = nil
+	saved.Trend = nil
+	data, err := json.Marshal(&saved)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode scan report: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reserveID(record)
+	if err := writeFileAtomic(filepath.Join(s.dir(), record.File), data); err != nil {
+		return "", fmt.Errorf("failed to write scan report: %w", err)
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode history record: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(s.dir(), indexFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open history index: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return "", fmt.Errorf("failed to append history index: %w", err)
+	}
+
+	log.Infoc(ctx, "Scan saved to history", log.Str("scan_id", record.ID))
+	return record.ID, nil
+}
+
+func (s *store) ListScans(ctx context.Context, query *Query) ([]*Record, error) {
+	if query == nil {
+		query = &Query{}
+	}
+	records, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	urlRe := urlPattern(query.URL)
+	matched := make([]*Record, 0)
+	for _, record := range records {
+		if !query.matchRecord(record) {
+			continue
+		}
+		// 按页面筛选时需要读取完整结果
+		if urlRe != nil {
+			report, err := s.GetScan(ctx, record.ID)
+			if err != nil {
+				return nil, err
+			}
+			if len(pageHits(record, report, urlRe, query.Keyword)) == 0 {
+				continue
+			}
+		}
+		matched = append(matched, record)
+		if query.Limit > 0 && len(matched) == query.Limit {
+			break
+		}
+	}
+	return matched, nil
+}
+
+func (s *store) GetScan(ctx context.Context, id string) (*model.ScanReport, error) {
+	records, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.ID == id {
+			return s.readScan(record)
+		}
+	}
+	return nil, fmt.Errorf("scan not found: %s", id)
+}
+
+func (s *store) QueryHits(ctx context.Context, query *Query) ([]*HitRecord, error) {
+	if query == nil {
+		query = &Query{}
+	}
+	records, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	urlRe := urlPattern(query.URL)
+	hits := make([]*HitRecord, 0)
+	for _, record := range records {
+		if !query.matchRecord(record) {
+			continue
+		}
+		report, err := s.readScan(record)
+		if err != nil {
+			return nil, err
+		}
+		for _, hit := range pageHits(record, report, urlRe, query.Keyword) {
+			hits = append(hits, hit)
+			if query.Limit > 0 && len(hits) == query.Limit {
+				return hits, nil
+			}
+		}
+	}
+	return hits, nil
+}
+
+func (s *store) Delete(ctx context.Context, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	remove := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	indexPath := filepath.Join(s.dir(), indexFileName)
+	data, err := os.ReadFile(indexPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read history index: %w", err)
+	}
+
+	// 先重写索引再删除结果文件，中断时不会留下指向不存在文件的记录
+	var kept bytes.Buffer
+	files := make([]string, 0, len(ids))
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		record := &Record{}
+		if err := json.Unmarshal(line, record); err == nil && remove[record.ID] {
+			files = append(files, filepath.Join(s.dir(), record.File))
+			continue
+		}
+		kept.Write(line)
+		kept.WriteByte('\n')
+	}
+	if err := writeFileAtomic(indexPath, kept.Bytes()); err != nil {
+		return 0, fmt.Errorf("failed to rewrite history index: %w", err)
+	}
+
+	var freed int64
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			freed += info.Size()
+		}
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Warnc(ctx, "Failed to remove history scan", log.Str("file", file), log.Err(err))
+		}
+	}
+	return freed, nil
+}
+
+// readIndex 读取全部扫描记录，按开始时间倒序排列
+func (s *store) readIndex() ([]*Record, error) {
+	s.mu.Lock()
+	data, err := os.ReadFile(filepath.Join(s.dir(), indexFileName))
+	s.mu.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return []*Record{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history index: %w", err)
+	}
+
+	records := make([]*Record, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		record := &Record{}
+		// 跳过写入中断留下的不完整行
+		if err := json.Unmarshal(line, record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse history index: %w", err)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].StartTime.After(records[j].StartTime)
+	})
+	return records, nil
+}
+
+func (s *store) readScan(record *Record) (*model.ScanReport, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir(), record.File))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scan %s: %w", record.ID, err)
+	}
+	report := &model.ScanReport{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("failed to parse scan %s: %w", record.ID, err)
+	}
+	return report, nil
+}
+
+func (q *Query) matchRecord(record *Record) bool {
+	if q.TargetURL != "" && record.TargetURL != q.TargetURL {
+		return false
+	}
+	if !q.Since.IsZero() && record.StartTime.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !record.StartTime.Before(q.Until) {
+		return false
+	}
+	if len(q.Modes) > 0 && !slices.Contains(q.Modes, record.ScanMode) {
+		return false
+	}
+	if q.Keyword != "" && !slices.Contains(record.MatchedKeywords, q.Keyword) {
+		return false
+	}
+	return true
+}
+
+// pageHits 返回扫描结果中符合页面与关键词条件的命中
+func pageHits(record *Record, report *model.ScanReport, urlRe *regexp.Regexp, keyword string) []*HitRecord {
+	hits := make([]*HitRecord, 0)
+	for _, result := range report.Results {
+		if urlRe != nil && !urlRe.MatchString(result.URL) {
+			continue
+		}
+		keywords := make([]string, 0, len(result.KeywordCounts))
+		for k := range result.KeywordCounts {
+			if keyword == "" || k == keyword {
+				keywords = append(keywords, k)
+			}
+		}
+		sort.Strings(keywords)
+		for _, k := range keywords {
+			hit := &HitRecord{
+				ScanID:    record.ID,
+				TargetURL: record.TargetURL,
+				StartTime: record.StartTime,
+				URL:       result.URL,
+				Keyword:   k,
+				Count:     result.KeywordCounts[k],
+			}
+			for _, h := range result.Hits {
+				if h.Keyword == k {
+					hit.Hits = append(hit.Hits, h)
+				}
+			}
+			hits = append(hits, hit)
+		}
+	}
+	return hits
+}
+
+// reserveID 同一目标在同一秒内扫描多次时，为 ID 追加序号，避免覆盖已保存的结果；调用方需持有 s.mu
+func (s *store) reserveID(record *Record) {
+	base := record.ID
+	for n := 2; fileExists(filepath.Join(s.dir(), record.File)); n++ {
+		record.ID = fmt.Sprintf("%s_%d", base, n)
+		record.File = scanFile(record.ID)
+	}
+}
+
+// newRecord 根据扫描报告生成索引记录
+func newRecord(report *model.ScanReport) *Record {
+	startTime, err := time.ParseInLocation(reportTimeLayout, report.StartTime, time.Local)
+	if err != nil {
+		startTime = time.Now()
+	}
+	endTime, err := time.ParseInLocation(reportTimeLayout, report.EndTime, time.Local)
+	if err != nil {
+		endTime = startTime
+	}
+
+	// 同一秒内可能扫描多个目标，ID 中加入目标地址的哈希
+	sum := sha256.Sum256([]byte(report.TargetURL))
+	id := fmt.Sprintf("%s_%s", startTime.Format("20060102_150405"), hex.EncodeToString(sum[:4]))
+
+	record := &Record{
+		ID:           id,
+		TargetURL:    report.TargetURL,
+		ScanMode:     report.ScanMode,
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Duration:     report.Duration,
+		Keywords:     report.Keywords,
+		TotalPages:   report.TotalPages,
+		MatchPages:   report.MatchPages,
+		ErrorCount:   report.ErrorCount,
+		FindingCount: len(report.Findings),
+		File:         scanFile(id),
+	}
+
+	matched := make(map[string]bool)
+	for _, result := range report.Results {
+		record.HitCount += result.TotalCount
+		for keyword, count := range result.KeywordCounts {
+			if count > 0 {
+				matched[keyword] = true
+				if record.KeywordCounts == nil {
+					record.KeywordCounts = make(map[string]int)
+				}
+				record.KeywordCounts[keyword] += count
+			}
+		}
+	}
+	for keyword := range matched {
+		record.MatchedKeywords = append(record.MatchedKeywords, keyword)
+	}
+	sort.Strings(record.MatchedKeywords)
+	return record
+}
+
+// scanFile 返回扫描结果文件相对历史目录的路径
+func scanFile(id string) string {
+	return filepath.ToSlash(filepath.Join(scansDirName, id+".json"))
+}
+
+func fileExists(filePath string) bool {
+	_, err := os.Stat(filePath)
+	return err == nil
+}
+
+// urlPattern 将 URL 条件转换为正则，* 匹配任意字符，为空时返回 nil
+func urlPattern(pattern string) *regexp.Regexp {
+	if pattern == "" {
+		return nil
+	}
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免中断时留下不完整的文件
+func writeFileAtomic(filePath string, data []byte) error {
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package matcher
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fuzzyPattern 按编辑距离匹配的关键词
+//
+// 不含汉字的关键词按单词比较，多个单词的关键词与相邻的同样数量的单词比较；
+// 含汉字的关键词在连续汉字中按长度相近的字符窗口比较。
+type fuzzyPattern struct {
+	keyword     string
+	target      []rune // 规范化并转为小写后的关键词
+	words       int    // 关键词包含的单词数，按字符窗口比较时为 0
+	maxDistance int
+}
+
+// fuzzyHit 模糊命中在匹配文本中的位置
+type fuzzyHit struct {
+	start, end int
+	distance   int
+}
+
+// textToken 文本中的一个单词或一段连续汉字
+type textToken struct {
+	start, end int    // 在文本中的字节偏移
+	runes      []rune // 转为小写后的字符
+	offsets    []int  // 每个字符的起始字节偏移，仅连续汉字记录
+}
+
+type textTokens struct {
+	words   []*textToken
+	hanRuns []*textToken
+}
+
+func newFuzzyPattern(keyword, text string, maxDistance int) *fuzzyPattern {
+	target := []rune(strings.ToLower(text))
+	hasHan := false
+	for _, r := range target {
+		if unicode.Is(unicode.Han, r) {
+			hasHan = true
+			break
+		}
+	}
+
+	// 距离需小于关键词长度的一半，否则短关键词几乎能命中任意文本
+	maxDistance = min(maxDistance, (len(target)-1)/2)
+	if maxDistance <= 0 {
+		return nil
+	}
+
+	p := &fuzzyPattern{keyword: keyword, target: target, maxDistance: maxDistance}
+	if !hasHan {
+		p.words = len(strings.FieldsFunc(text, func(r rune) bool { return !isWordRune(r) }))
+		if p.words == 0 {
+			return nil
+		}
+		p.target = []rune(strings.Join(strings.FieldsFunc(string(target), func(r rune) bool { return !isWordRune(r) }), " "))
+	}
+	return p
+}
+
+// find 查找起点不早于 from 且编辑距离在 1 到 maxDistance 之间的命中，精确命中由正则表达式处理
+func (p *fuzzyPattern) find(tokens *textTokens, from int) []*fuzzyHit {
+	if p.words > 0 {
+		return p.findWords(tokens.words, from)
+	}
+	return p.findHan(tokens.hanRuns, from)
+}
+
+func (p *fuzzyPattern) findWords(words []*textToken, from int) []*fuzzyHit {
+	hits := make([]*fuzzyHit, 0)
+	cache := make(map[string]int)
+
+	for i := 0; i+p.words <= len(words); i++ {
+		first, last := words[i], words[i+p.words-1]
+		if first.start < from {
+			continue
+		}
+
+		length := p.words - 1
+		for _, w := range words[i : i+p.words] {
+			length += len(w.runes)
+		}
+		if abs(length-len(p.target)) > p.maxDistance {
+			continue
+		}
+
+		candidate := first.runes
+		if p.words > 1 {
+			parts := make([]string, 0, p.words)
+			for _, w := range words[i : i+p.words] {
+				parts = append(parts, string(w.runes))
+			}
+			candidate = []rune(strings.Join(parts, " "))
+		}
+
+		key := string(candidate)
+		distance, ok := cache[key]
+		if !ok {
+			distance = osaDistance(candidate, p.target, p.maxDistance)
+			cache[key] = distance
+		}
+		if distance > 0 && distance <= p.maxDistance {
+			hits = append(hits, &fuzzyHit{start: first.start, end: last.end, distance: distance})
+			i += p.words - 1
+		}
+	}
+
+	return hits
+}
+
+func (p *fuzzyPattern) findHan(runs []*textToken, from int) []*fuzzyHit {
+	hits := make([]*fuzzyHit, 0)
+	n := len(p.target)
+	targetRunes := make(map[rune]bool, n)
+	for _, r := range p.target {
+		targetRunes[r] = true
+	}
+
+	for _, run := range runs {
+		if run.end <= from {
+			continue
+		}
+		for i := 0; i < len(run.runes); {
+			if run.offsets[i] < from {
+				i++
+				continue
+			}
+
+			// 窗口中与关键词相同的字符少于 n-maxDistance 个时不可能命中
+			common := 0
+			for _, r := range run.runes[i:min(i+n+p.maxDistance, len(run.runes))] {
+				if targetRunes[r] {
+					common++
+				}
+			}
+			if common < n-p.maxDistance {
+				i++
+				continue
+			}
+
+			// 在以 i 开始、长度相近的窗口中选择距离最小的一个
+			bestLen, bestDistance := 0, p.maxDistance+1
+			for l := max(n-p.maxDistance, 1); l <= n+p.maxDistance && i+l <= len(run.runes); l++ {
+				distance := osaDistance(run.runes[i:i+l], p.target, p.maxDistance)
+				if distance < bestDistance || (distance == bestDistance && abs(l-n) < abs(bestLen-n)) {
+					bestLen, bestDistance = l, distance
+				}
+			}
+
+			switch {
+			case bestDistance == 0:
+				i += bestLen
+			case bestDistance <= p.maxDistance:
+				end := run.end
+				if i+bestLen < len(run.runes) {
+					end = run.offsets[i+bestLen]
+				}
+				hits = append(hits, &fuzzyHit{start: run.offsets[i], end: end, distance: bestDistance})
+				i += bestLen
+			default:
+				i++
+			}
+		}
+	}
+
+	return hits
+}
+
+// tokenizeText 将文本拆分为单词与连续汉字，单词转为小写
+func tokenizeText(text string) *textTokens {
+	tokens := &textTokens{
+		words:   make([]*textToken, 0),
+		hanRuns: make([]*textToken, 0),
+	}
+
+	var current *textToken
+	currentHan := false
+	flush := func(end int) {
+		if current == nil {
+			return
+		}
+		current.end = end
+		if currentHan {
+			tokens.hanRuns = append(tokens.hanRuns, current)
+		} else {
+			tokens.words = append(tokens.words, current)
+		}
+		current = nil
+	}
+
+	for offset, r := range text {
+		han := unicode.Is(unicode.Han, r)
+		if !han && !isWordRune(r) {
+			flush(offset)
+			continue
+		}
+		if current != nil && han != currentHan {
+			flush(offset)
+		}
+		if current == nil {
+			current = &textToken{start: offset}
+			currentHan = han
+		}
+		if han {
+			current.offsets = append(current.offsets, offset)
+		}
+		current.runes = append(current.runes, unicode.ToLower(r))
+	}
+	flush(len(text))
+
+	return tokens
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r)) && !unicode.Is(unicode.Han, r)
+}
+
+// osaDistance 计算按字符的编辑距离（限制型 Damerau-Levenshtein，相邻字符交换计为一次编辑），
+// 超过 maxDistance 时提前返回 maxDistance+1
+func osaDistance(a, b []rune, maxDistance int) int {
+	if abs(len(a)-len(b)) > maxDistance {
+		return maxDistance + 1
+	}
+
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > maxDistance {
+			return maxDistance + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return min(prev[len(b)], maxDistance+1)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
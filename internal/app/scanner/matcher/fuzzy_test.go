@@ -0,0 +1,149 @@
+package matcher
+
+import "testing"
+
+func TestOSADistance(t *testing.T) {
+	tests := []struct {
+		name        string
+		a, b        string
+		maxDistance int
+		want        int
+	}{
+		{name: "equal", a: "casino", b: "casino", maxDistance: 2, want: 0},
+		{name: "substitution", a: "cazino", b: "casino", maxDistance: 2, want: 1},
+		{name: "insertion", a: "casinos", b: "casino", maxDistance: 2, want: 1},
+		{name: "deletion", a: "casno", b: "casino", maxDistance: 2, want: 1},
+		{name: "transposition", a: "csaino", b: "casino", maxDistance: 2, want: 1},
+		{name: "transposition at end", a: "casion", b: "casino", maxDistance: 2, want: 1},
+		{name: "two edits", a: "kasinos", b: "casino", maxDistance: 2, want: 2},
+		// 限制型编辑距离不允许对交换过的字符再次编辑，ca → abc 为 3 而不是 2
+		{name: "restricted transposition", a: "ca", b: "abc", maxDistance: 3, want: 3},
+		{name: "empty", a: "", b: "abc", maxDistance: 3, want: 3},
+		{name: "han substitution", a: "赌搏网站", b: "赌博网站", maxDistance: 1, want: 1},
+		{name: "han transposition", a: "博赌网站", b: "赌博网站", maxDistance: 1, want: 1},
+		{name: "han deletion", a: "赌博站", b: "赌博网站", maxDistance: 1, want: 1},
+		{name: "exceeds max distance", a: "kitten", b: "sitting", maxDistance: 2, want: 3},
+		{name: "length difference exceeds max distance", a: "casino", b: "casinoroyale", maxDistance: 2, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := osaDistance([]rune(tt.a), []rune(tt.b), tt.maxDistance); got != tt.want {
+				t.Errorf("osaDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.maxDistance, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFuzzyPatternMaxDistance(t *testing.T) {
+	tests := []struct {
+		keyword     string
+		maxDistance int
+		want        int // 0 表示不启用模糊匹配
+	}{
+		{keyword: "casino", maxDistance: 2, want: 2},
+		{keyword: "casino", maxDistance: 5, want: 2},
+		{keyword: "dubo", maxDistance: 1, want: 1},
+		{keyword: "db", maxDistance: 1, want: 0},
+		{keyword: "赌博网站", maxDistance: 2, want: 1},
+		{keyword: "赌博", maxDistance: 1, want: 0},
+	}
+
+	for _, tt := range tests {
+		p := newFuzzyPattern(tt.keyword, tt.keyword, tt.maxDistance)
+		got := 0
+		if p != nil {
+			got = p.maxDistance
+		}
+		if got != tt.want {
+			t.Errorf("newFuzzyPattern(%q, %d) max distance = %d, want %d", tt.keyword, tt.maxDistance, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyPatternFind(t *testing.T) {
+	tests := []struct {
+		name        string
+		keyword     string
+		maxDistance int
+		text        string
+		from        int
+		want        []string // 命中的原文
+		distances   []int
+	}{
+		{
+			name: "word substitution", keyword: "casino", maxDistance: 2,
+			text: "play cazino now", want: []string{"cazino"}, distances: []int{1},
+		},
+		{
+			name: "exact match is left to the regexp", keyword: "casino", maxDistance: 2,
+			text: "play casino now", want: []string{},
+		},
+		{
+			name: "word transposition", keyword: "casino", maxDistance: 1,
+			text: "csaino", want: []string{"csaino"}, distances: []int{1},
+		},
+		{
+			name: "case insensitive", keyword: "casino", maxDistance: 1,
+			text: "CAZINO", want: []string{"CAZINO"}, distances: []int{1},
+		},
+		{
+			name: "word inside a longer word is not a window", keyword: "casino", maxDistance: 1,
+			text: "occasional", want: []string{},
+		},
+		{
+			name: "multiple words", keyword: "online poker", maxDistance: 2,
+			text: "try Online, Pokr today", want: []string{"Online, Pokr"}, distances: []int{1},
+		},
+		{
+			name: "hits before from are skipped", keyword: "casino", maxDistance: 1,
+			text: "cazino casimo", from: 1, want: []string{"casimo"}, distances: []int{1},
+		},
+		{
+			name: "han substitution in a run", keyword: "赌博网站", maxDistance: 1,
+			text: "最大的赌搏网站", want: []string{"赌搏网站"}, distances: []int{1},
+		},
+		{
+			name: "han transposition", keyword: "赌博网站", maxDistance: 1,
+			text: "博赌网站", want: []string{"博赌网站"}, distances: []int{1},
+		},
+		{
+			name: "han window at the end of a run", keyword: "赌博网站", maxDistance: 1,
+			text: "这是赌博网", want: []string{"赌博网"}, distances: []int{1},
+		},
+		{
+			name: "han exact match is skipped", keyword: "赌博网站", maxDistance: 1,
+			text: "赌博网站", want: []string{},
+		},
+		{
+			name: "han runs are split by other text", keyword: "赌博网站", maxDistance: 1,
+			text: "赌博 网站", want: []string{},
+		},
+		{
+			name: "han after multibyte punctuation", keyword: "赌博网站", maxDistance: 1,
+			text: "“赌搏网站”", want: []string{"赌搏网站"}, distances: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newFuzzyPattern(tt.keyword, tt.keyword, tt.maxDistance)
+			if p == nil {
+				t.Fatalf("newFuzzyPattern(%q, %d) = nil", tt.keyword, tt.maxDistance)
+			}
+
+			hits := p.find(tokenizeText(tt.text), tt.from)
+			if len(hits) != len(tt.want) {
+				t.Fatalf("find(%q) = %d hits, want %d", tt.text, len(hits), len(tt.want))
+			}
+			for i, hit := range hits {
+				if got := tt.text[hit.start:hit.end]; got != tt.want[i] {
+					t.Errorf("hit %d = %q, want %q", i, got, tt.want[i])
+				}
+				if hit.distance != tt.distances[i] {
+					t.Errorf("hit %d distance = %d, want %d", i, hit.distance, tt.distances[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package matcher
+
+import (
+	"io"
+)
+
+// Match 表示一次关键词命中
+type Match struct {
+	Keyword  string // 配置的关键词
+	Start    int    // 命中内容在原文中的起始字节偏移
+	End      int    // 命中内容在原文中的结束字节偏移（不含）
+	Variant  string // 命中的繁简、拼音写法或模糊命中的原文，直接命中关键词时为空
+	Distance int    // 模糊命中的编辑距离，其他命中为 0
+}
+
+// Matcher 关键词匹配器接口
+type Matcher interface {
+	// Match 在文本中查找所有关键词命中，按出现位置排序
+	Match(text string) []*Match
+	// MatchReader 以流式方式读取内容并查找关键词命中，不在内存中保留完整内容
+	MatchReader(r io.Reader) ([]*Match, error)
+}
@@ -0,0 +1,340 @@
+package matcher
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+)
+
+const (
+	streamChunkSize = 32 * 1024
+	// maxSeparatorsLimit 关键词字符间允许的分隔字符数上限
+	maxSeparatorsLimit = 16
+)
+
+type pattern struct {
+	keyword  string
+	variants []string // 与正则表达式中的分组一一对应
+	re       *regexp.Regexp
+}
+
+type matcher struct {
+	patterns  []*pattern
+	fuzzy     []*fuzzyPattern
+	normalize bool // 是否在匹配前规范化文本
+	// window 流式匹配时保留的尾部字节数，需不小于任一关键词可能命中的最大字节长度
+	window int
+}
+
+// NewMatcher 根据扫描配置创建关键词匹配器
+func NewMatcher(cfg *localcfg.ScannerConfig) Matcher {
+	m := &matcher{
+		patterns: make([]*pattern, 0, len(cfg.Keywords)),
+	}
+
+	maxSeparators := 0
+	if cfg.Normalize != nil {
+		m.normalize = cfg.Normalize.Enabled
+		maxSeparators = min(max(cfg.Normalize.MaxSeparators, 0), maxSeparatorsLimit)
+	}
+
+	rules := make(map[string]*localcfg.KeywordRuleConfig)
+	for _, rule := range cfg.KeywordRules {
+		if rule != nil {
+			rules[rule.Keyword] = rule
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, keyword := range cfg.Keywords {
+		if keyword == "" || seen[keyword] {
+			continue
+		}
+		seen[keyword] = true
+
+		rule := rules[keyword]
+		p := m.newPattern(keyword, rule, maxSeparators)
+		if p == nil {
+			continue
+		}
+		m.patterns = append(m.patterns, p)
+
+		if rule != nil && rule.MaxDistance > 0 {
+			text := keyword
+			if m.normalize {
+				text = normalize(keyword).text
+			}
+			if fp := newFuzzyPattern(keyword, text, rule.MaxDistance); fp != nil {
+				m.fuzzy = append(m.fuzzy, fp)
+				// 模糊命中最多比关键词多 maxDistance 个字符
+				if window := (len(fp.target) + fp.maxDistance) * utf8.UTFMax * 2; window > m.window {
+					m.window = window
+				}
+			}
+		}
+	}
+
+	return m
+}
+
+// newPattern 将关键词及其繁简、拼音写法合并为一个正则表达式，每种写法对应一个分组
+func (m *matcher) newPattern(keyword string, rule *localcfg.KeywordRuleConfig, maxSeparators int) *pattern {
+	variants := []string{keyword}
+	if rule != nil && rule.ChineseVariants {
+		variants = append(variants, chineseVariants(keyword)...)
+	}
+	// 拼音写法只匹配完整单词，避免首字母等短写法误命中
+	wordFrom := len(variants)
+	if rule != nil && (rule.Pinyin || rule.PinyinInitials) {
+		variants = append(variants, pinyinVariants(keyword, rule.Pinyin, rule.PinyinInitials)...)
+	}
+
+	p := &pattern{keyword: keyword}
+	alternatives := make([]string, 0, len(variants))
+	for i, variant := range variants {
+		// 关键词与页面文本使用相同的规范化，保证全角、形近字母等写法一致
+		text := variant
+		if m.normalize {
+			text = normalize(variant).text
+			if text == "" {
+				continue
+			}
+		}
+
+		expr := "(" + keywordPattern(text, maxSeparators) + ")"
+		if i >= wordFrom {
+			expr = `\b` + expr + `\b`
+		}
+		alternatives = append(alternatives, expr)
+		p.variants = append(p.variants, variant)
+
+		// 忽略大小写时命中内容的字节长度可能与关键词不同，按每个字符最大字节数估算
+		runes := utf8.RuneCountInString(text)
+		window := (runes + (runes-1)*maxSeparators) * utf8.UTFMax
+		if m.normalize {
+			// 原文中插入的零宽字符、全角字符会使命中内容长于规范化后的文本
+			window *= 2
+		}
+		if window > m.window {
+			m.window = window
+		}
+	}
+	if len(alternatives) == 0 {
+		return nil
+	}
+
+	// 使用正则表达式进行不区分大小写的搜索
+	p.re = regexp.MustCompile(`(?i)` + strings.Join(alternatives, "|"))
+	return p
+}
+
+// newMatch 根据命中的分组确定匹配到的写法
+func (p *pattern) newMatch(loc []int, nt *normalizedText) *Match {
+	start, end := nt.origRange(loc[0], loc[1])
+	match := &Match{Keyword: p.keyword, Start: start, End: end}
+	for i, variant := range p.variants {
+		if loc[2*i+2] >= 0 {
+			if variant != p.keyword {
+				match.Variant = variant
+			}
+			break
+		}
+	}
+	return match
+}
+
+// keywordPattern 生成关键词的正则表达式，允许相邻字符之间出现有限个空白、标点或格式字符
+func keywordPattern(keyword string, maxSeparators int) string {
+	if maxSeparators <= 0 {
+		return regexp.QuoteMeta(keyword)
+	}
+
+	parts := make([]string, 0, len(keyword))
+	for _, r := range keyword {
+		parts = append(parts, regexp.QuoteMeta(string(r)))
+	}
+	return strings.Join(parts, fmt.Sprintf(`[\s\p{P}\p{S}\p{Cf}]{0,%d}`, maxSeparators))
+}
+
+// prepare 返回用于匹配的文本，未启用规范化时与原文相同
+func (m *matcher) prepare(text string) *normalizedText {
+	if !m.normalize {
+		return &normalizedText{text: text}
+	}
+	return normalize(text)
+}
+
+func (m *matcher) Match(text string) []*Match {
+	matches := make([]*Match, 0)
+	nt := m.prepare(text)
+	for _, p := range m.patterns {
+		for _, loc := range p.re.FindAllStringSubmatchIndex(nt.text, -1) {
+			matches = append(matches, p.newMatch(loc, nt))
+		}
+	}
+
+	if len(m.fuzzy) > 0 {
+		tokens := tokenizeText(nt.text)
+		for _, fp := range m.fuzzy {
+			for _, hit := range fp.find(tokens, 0) {
+				matches = append(matches, fp.newMatch(hit, nt, text))
+			}
+		}
+	}
+
+	sortMatches(matches)
+	return dropOverlapped(matches)
+}
+
+func (m *matcher) MatchReader(r io.Reader) ([]*Match, error) {
+	matches := make([]*Match, 0)
+	if len(m.patterns) == 0 {
+		_, err := io.Copy(io.Discard, r)
+		return matches, err
+	}
+
+	chunk := make([]byte, streamChunkSize)
+	buf := make([]byte, 0, streamChunkSize+m.window)
+	bufStart := 0 // buf[0] 在原文中的偏移
+	// next 记录每个关键词下一次搜索的起点，保证与一次性匹配的结果一致
+	next := make([]int, len(m.patterns))
+	fuzzyNext := make([]int, len(m.fuzzy))
+	midWord := false // buf 是否从一个过长单词的中间开始
+
+	for {
+		n, err := r.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+
+		eof := errors.Is(err, io.EOF)
+		if err != nil && !eof {
+			return nil, err
+		}
+
+		// 起点在 boundary 之前的命中已完整落在 buf 中
+		boundary := bufStart + len(buf)
+		if !eof {
+			boundary -= m.window
+		}
+
+		nt := m.prepare(string(buf))
+		for i, p := range m.patterns {
+			from := next[i] - bufStart
+			if from < 0 {
+				from = 0
+			}
+			normFrom := nt.normOffset(from)
+			for _, loc := range p.re.FindAllStringSubmatchIndex(nt.text[normFrom:], -1) {
+				for j := range loc {
+					if loc[j] >= 0 {
+						loc[j] += normFrom
+					}
+				}
+				match := p.newMatch(loc, nt)
+				match.Start += bufStart
+				if match.Start >= boundary {
+					break
+				}
+				match.End += bufStart
+				matches = append(matches, match)
+				next[i] = match.End
+			}
+		}
+
+		if len(m.fuzzy) > 0 {
+			tokens := tokenizeText(nt.text)
+			skip := 0
+			if midWord && len(tokens.words) > 0 && tokens.words[0].start == 0 {
+				skip = tokens.words[0].end
+			}
+			for i, fp := range m.fuzzy {
+				from := max(fuzzyNext[i]-bufStart, 0)
+				for _, hit := range fp.find(tokens, max(nt.normOffset(from), skip)) {
+					match := fp.newMatch(hit, nt, string(buf))
+					match.Start += bufStart
+					if match.Start >= boundary {
+						break
+					}
+					match.End += bufStart
+					matches = append(matches, match)
+					fuzzyNext[i] = match.End
+				}
+			}
+		}
+
+		if eof {
+			break
+		}
+
+		// 只保留尾部窗口，并对齐到字符边界
+		keepFrom := boundary - bufStart
+		if keepFrom < 0 {
+			keepFrom = 0
+		}
+		for keepFrom > 0 && keepFrom < len(buf) && !utf8.RuneStart(buf[keepFrom]) {
+			keepFrom--
+		}
+		// 尽量从单词开头保留，避免单词片段被当作完整单词匹配
+		limit := max(keepFrom-m.window, 0)
+		for keepFrom > limit {
+			r, size := utf8.DecodeLastRune(buf[:keepFrom])
+			if !isWordRune(r) {
+				break
+			}
+			keepFrom -= size
+		}
+		r, _ := utf8.DecodeLastRune(buf[:keepFrom])
+		midWord = keepFrom > 0 && isWordRune(r)
+		buf = append(buf[:0], buf[keepFrom:]...)
+		bufStart += keepFrom
+	}
+
+	sortMatches(matches)
+	return dropOverlapped(matches), nil
+}
+
+func sortMatches(matches []*Match) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+}
+
+// newMatch 将模糊命中映射回原文，命中的原文作为变体写法
+func (p *fuzzyPattern) newMatch(hit *fuzzyHit, nt *normalizedText, text string) *Match {
+	start, end := nt.origRange(hit.start, hit.end)
+	return &Match{
+		Keyword:  p.keyword,
+		Start:    start,
+		End:      end,
+		Variant:  text[start:end],
+		Distance: hit.distance,
+	}
+}
+
+// dropOverlapped 移除与同一关键词精确命中重叠的模糊命中，输入需已按位置排序
+func dropOverlapped(matches []*Match) []*Match {
+	exact := make(map[string][]*Match)
+	for _, match := range matches {
+		if match.Distance == 0 {
+			exact[match.Keyword] = append(exact[match.Keyword], match)
+		}
+	}
+
+	kept := matches[:0]
+	for _, match := range matches {
+		if match.Distance > 0 {
+			list := exact[match.Keyword]
+			i := sort.Search(len(list), func(i int) bool { return list[i].End > match.Start })
+			if i < len(list) && list[i].Start < match.End {
+				continue
+			}
+		}
+		kept = append(kept, match)
+	}
+	return kept
+}
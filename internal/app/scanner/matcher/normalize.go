@@ -0,0 +1,177 @@
+package matcher
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// span 规范化文本与原文之间的一段偏移映射
+type span struct {
+	norm, normLen int // 在规范化文本中的起始偏移与长度
+	orig, origLen int // 在原文中的起始偏移与长度
+	identity      bool
+}
+
+// normalizedText 规范化后的文本及其到原文的偏移映射，spans 为空时与原文相同
+type normalizedText struct {
+	text  string
+	spans []span
+}
+
+// normalize 对文本依次进行 NFKC 规范化（含全角、半角折叠）、去除零宽字符与形近字母映射
+func normalize(text string) *normalizedText {
+	var sb strings.Builder
+	sb.Grow(len(text))
+	nt := &normalizedText{spans: make([]span, 0)}
+
+	var it norm.Iter
+	it.InitString(norm.NFKC, text)
+	for !it.Done() {
+		start := it.Pos()
+		seg := it.Next()
+		end := it.Pos()
+
+		if string(seg) != text[start:end] {
+			// NFKC 改变了该段内容，整段作为一个映射单元
+			folded := foldString(string(seg))
+			if folded != "" {
+				nt.add(sb.Len(), len(folded), start, end-start, false)
+				sb.WriteString(folded)
+			}
+			continue
+		}
+
+		// 内容未变的段逐字符处理，保证映射精确到字符
+		for offset, r := range text[start:end] {
+			size := utf8.RuneLen(r)
+			if r == utf8.RuneError {
+				size = 1
+			}
+			pos := start + offset
+			if isZeroWidth(r) {
+				continue
+			}
+			if mapped, ok := confusables[r]; ok {
+				nt.add(sb.Len(), utf8.RuneLen(mapped), pos, size, false)
+				sb.WriteRune(mapped)
+				continue
+			}
+			nt.add(sb.Len(), size, pos, size, true)
+			sb.WriteString(text[pos : pos+size])
+		}
+	}
+
+	nt.text = sb.String()
+	return nt
+}
+
+// add 追加映射，与上一段连续且均未改变内容时合并
+func (nt *normalizedText) add(normStart, normLen, origStart, origLen int, identity bool) {
+	if identity && len(nt.spans) > 0 {
+		last := &nt.spans[len(nt.spans)-1]
+		if last.identity && last.norm+last.normLen == normStart && last.orig+last.origLen == origStart {
+			last.normLen += normLen
+			last.origLen += origLen
+			return
+		}
+	}
+	nt.spans = append(nt.spans, span{norm: normStart, normLen: normLen, orig: origStart, origLen: origLen, identity: identity})
+}
+
+// origRange 将规范化文本中的区间映射回原文
+func (nt *normalizedText) origRange(start, end int) (int, int) {
+	if nt.spans == nil {
+		return start, end
+	}
+
+	first := nt.spanAt(start)
+	origStart := first.orig
+	if first.identity {
+		origStart += start - first.norm
+	}
+
+	last := nt.spanAt(end - 1)
+	origEnd := last.orig + last.origLen
+	if last.identity {
+		origEnd = last.orig + (end - last.norm)
+	}
+
+	return origStart, origEnd
+}
+
+// normOffset 返回原文偏移之后第一个规范化文本偏移
+func (nt *normalizedText) normOffset(orig int) int {
+	if nt.spans == nil {
+		return orig
+	}
+
+	i := sort.Search(len(nt.spans), func(i int) bool {
+		return nt.spans[i].orig+nt.spans[i].origLen > orig
+	})
+	if i == len(nt.spans) {
+		return len(nt.text)
+	}
+
+	s := nt.spans[i]
+	switch {
+	case orig <= s.orig:
+		return s.norm
+	case s.identity:
+		return s.norm + (orig - s.orig)
+	default:
+		return s.norm + s.normLen
+	}
+}
+
+func (nt *normalizedText) spanAt(offset int) span {
+	i := sort.Search(len(nt.spans), func(i int) bool {
+		return nt.spans[i].norm+nt.spans[i].normLen > offset
+	})
+	if i == len(nt.spans) {
+		i--
+	}
+	return nt.spans[i]
+}
+
+// foldString 对已经过 NFKC 的文本去除零宽字符并映射形近字母
+func foldString(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isZeroWidth(r) {
+			return -1
+		}
+		if mapped, ok := confusables[r]; ok {
+			return mapped
+		}
+		return r
+	}, s)
+}
+
+// isZeroWidth 判断是否为不可见的零宽或格式字符
+func isZeroWidth(r rune) bool {
+	switch r {
+	case '\u00ad', '\u034f', '\u061c', '\u115f', '\u1160', '\u17b4', '\u17b5', '\u180e',
+		'\u200b', '\u200c', '\u200d', '\u200e', '\u200f', '\u2060', '\u2061', '\u2062', '\u2063', '\u2064',
+		'\u3164', '\ufeff', '\uffa0':
+		return true
+	}
+	return false
+}
+
+// confusables 常见的西里尔、希腊形近字母到拉丁字母的映射
+var confusables = map[rune]rune{
+	// 西里尔小写
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's', 'і': 'i', 'ј': 'j', 'ԁ': 'd',
+	'ԛ': 'q', 'ԝ': 'w', 'ѵ': 'v', 'һ': 'h', 'ӏ': 'l', 'ь': 'b',
+	// 西里尔大写
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O', 'Р': 'P',
+	'С': 'C', 'Т': 'T', 'У': 'Y', 'Х': 'X', 'Ѕ': 'S', 'І': 'I', 'Ј': 'J', 'Ԁ': 'D',
+	'Ԛ': 'Q', 'Ԝ': 'W', 'Ѵ': 'V', 'Һ': 'H', 'Ӏ': 'I',
+	// 希腊字母
+	'α': 'a', 'ο': 'o', 'ρ': 'p', 'ν': 'v', 'ι': 'i', 'κ': 'k', 'τ': 't', 'χ': 'x',
+	'υ': 'u', 'ϲ': 'c', 'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I',
+	'Κ': 'K', 'Μ': 'M', 'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
@@ -0,0 +1,171 @@
+package matcher
+
+import (
+	"bufio"
+	"embed"
+	"strings"
+	"sync"
+)
+
+// 繁简对照表来自 OpenCC（Apache-2.0），拼音表由 pinyin-data（MIT）整理，已去除声调，ü 记为 v
+// 许可证与来源说明见 data/NOTICE
+//
+//go:embed data/STCharacters.txt data/TSCharacters.txt data/pinyin.txt
+var dataFS embed.FS
+
+const (
+	// maxScriptVariants 单个关键词最多扩展的繁简写法数
+	maxScriptVariants = 32
+	// maxPinyinVariants 单个关键词最多扩展的拼音写法数（多音字组合）
+	maxPinyinVariants = 8
+)
+
+var (
+	tablesOnce     sync.Once
+	scriptVariants map[rune][]rune   // 字符对应的其他繁简写法
+	pinyinTable    map[rune][]string // 字符对应的拼音，常用读音在前
+)
+
+func loadTables() {
+	tablesOnce.Do(func() {
+		scriptVariants = make(map[rune][]rune)
+		for _, name := range []string{"data/STCharacters.txt", "data/TSCharacters.txt"} {
+			eachLine(name, func(key string, values []string) {
+				from := []rune(key)
+				if len(from) != 1 {
+					return
+				}
+				for _, value := range values {
+					to := []rune(value)
+					if len(to) != 1 || to[0] == from[0] {
+						continue
+					}
+					addScriptVariant(from[0], to[0])
+					addScriptVariant(to[0], from[0])
+				}
+			})
+		}
+
+		pinyinTable = make(map[rune][]string)
+		eachLine("data/pinyin.txt", func(key string, values []string) {
+			if r := []rune(key); len(r) == 1 {
+				pinyinTable[r[0]] = strings.Split(values[0], ",")
+			}
+		})
+	})
+}
+
+func addScriptVariant(from, to rune) {
+	for _, r := range scriptVariants[from] {
+		if r == to {
+			return
+		}
+	}
+	scriptVariants[from] = append(scriptVariants[from], to)
+}
+
+// eachLine 逐行读取内嵌的制表符分隔文件，第二列按空格拆分
+func eachLine(name string, fn func(key string, values []string)) {
+	f, err := dataFS.Open(name)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "\t")
+		if ok && value != "" {
+			fn(key, strings.Fields(value))
+		}
+	}
+}
+
+// chineseVariants 返回关键词的繁体、简体及混合写法，不含关键词本身
+func chineseVariants(keyword string) []string {
+	loadTables()
+
+	candidates := make([][]string, 0)
+	for _, r := range keyword {
+		options := []string{string(r)}
+		for _, v := range scriptVariants[r] {
+			options = append(options, string(v))
+		}
+		candidates = append(candidates, options)
+	}
+
+	return without(combine(candidates, maxScriptVariants), keyword)
+}
+
+// pinyinVariants 返回关键词的全拼与首字母写法，非汉字字符保持不变
+func pinyinVariants(keyword string, full, initials bool) []string {
+	loadTables()
+
+	candidates := make([][]string, 0)
+	hasHan := false
+	for _, r := range keyword {
+		readings, ok := pinyinTable[r]
+		if !ok {
+			candidates = append(candidates, []string{strings.ToLower(string(r))})
+			continue
+		}
+		hasHan = true
+		candidates = append(candidates, readings)
+	}
+	if !hasHan {
+		return nil
+	}
+
+	variants := make([]string, 0)
+	if full {
+		variants = append(variants, combine(candidates, maxPinyinVariants)...)
+	}
+	if initials {
+		initialCandidates := make([][]string, 0, len(candidates))
+		for _, options := range candidates {
+			seen := make(map[string]bool)
+			letters := make([]string, 0, len(options))
+			for _, option := range options {
+				if letter := option[:1]; !seen[letter] {
+					seen[letter] = true
+					letters = append(letters, letter)
+				}
+			}
+			initialCandidates = append(initialCandidates, letters)
+		}
+		variants = append(variants, combine(initialCandidates, maxPinyinVariants)...)
+	}
+
+	return without(variants, keyword)
+}
+
+// combine 按顺序组合每个位置的候选写法，超过上限时其余位置只取首个候选
+func combine(candidates [][]string, limit int) []string {
+	results := []string{""}
+	for _, options := range candidates {
+		if len(results)*len(options) > limit {
+			options = options[:1]
+		}
+		next := make([]string, 0, len(results)*len(options))
+		for _, prefix := range results {
+			for _, option := range options {
+				next = append(next, prefix+option)
+			}
+		}
+		results = next
+	}
+	return results
+}
+
+// without 去重并移除指定的字符串
+func without(list []string, exclude string) []string {
+	seen := map[string]bool{exclude: true}
+	results := make([]string, 0, len(list))
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			results = append(results, s)
+		}
+	}
+	return results
+}
@@ -9,5 +9,5 @@ import (
 // Notifier 通知器接口
 type Notifier interface {
 	// Notify 发送扫描完成通知
-	Notify(ctx context.Context, report *model.ScanReport, filePath string) error
+	Notify(ctx context.Context, report *model.ScanReport, filePaths []string) error
 }
@@ -3,18 +3,20 @@ package notifier
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
 	"github.com/gw-gong/key-spy/internal/pkg/client/wechat"
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
 	"github.com/gw-gong/key-spy/internal/pkg/model"
 
 	"github.com/gw-gong/gwkit-go/log"
 )
 
 type notifier struct {
-	cfg           *localcfg.Config
-	wechatClient  *wechat.WebhookClient
+	cfg          *localcfg.Config
+	wechatClient *wechat.WebhookClient
 }
 
 // NewNotifier 创建通知器
@@ -31,15 +33,21 @@ func NewNotifier(cfg *localcfg.Config) Notifier {
 }
 
 // Notify 发送扫描完成通知
-func (n *notifier) Notify(ctx context.Context, report *model.ScanReport, filePath string) error {
+func (n *notifier) Notify(ctx context.Context, report *model.ScanReport, filePaths []string) error {
 	if n.cfg.Notifier == nil || !n.cfg.Notifier.Enabled {
 		log.Debugc(ctx, "notifier is disabled, skip sending notification")
 		return nil
 	}
 
+	if n.cfg.Notifier.OnlyChanges && report.Diff != nil &&
+		len(report.Diff.NewHits) == 0 && len(report.Diff.NewFindings) == 0 {
+		log.Infoc(ctx, "no new hits or findings since last scan, skip sending notification")
+		return nil
+	}
+
 	// 发送企业微信通知
 	if n.wechatClient != nil {
-		if err := n.sendWechatNotification(ctx, report, filePath); err != nil {
+		if err := n.sendWechatNotification(ctx, report, filePaths); err != nil {
 			log.Errorc(ctx, "failed to send wechat notification", log.Err(err))
 			return err
 		}
@@ -49,8 +57,13 @@ func (n *notifier) Notify(ctx context.Context, report *model.ScanReport, filePat
 }
 
 // sendWechatNotification 发送企业微信通知
-func (n *notifier) sendWechatNotification(ctx context.Context, report *model.ScanReport, filePath string) error {
-	content := n.formatMarkdownContent(report, filePath)
+func (n *notifier) sendWechatNotification(ctx context.Context, report *model.ScanReport, filePaths []string) error {
+	// 通知的语言与时区未设置时沿用扫描目标的配置
+	p, err := i18n.NewPrinter(n.cfg.Notifier.Locale.Resolve(n.cfg.Scanner.Locale))
+	if err != nil {
+		return fmt.Errorf("failed to create printer: %w", err)
+	}
+	content := n.formatMarkdownContent(p, report, filePaths)
 
 	msg := &wechat.MarkdownMessage{
 		Content: content,
@@ -65,39 +78,117 @@ func (n *notifier) sendWechatNotification(ctx context.Context, report *model.Sca
 }
 
 // formatMarkdownContent 格式化 Markdown 内容
-func (n *notifier) formatMarkdownContent(report *model.ScanReport, filePath string) string {
+func (n *notifier) formatMarkdownContent(p *i18n.Printer, report *model.ScanReport, filePaths []string) string {
 	var sb strings.Builder
 
 	// 标题
-	sb.WriteString("## 🔍 Key-Spy 扫描报告\n\n")
+	if report.ScanMode == model.ScanModeOffline {
+		sb.WriteString("## 🔍 " + p.T("notify.title_offline") + "\n\n")
+	} else {
+		sb.WriteString("## 🔍 " + p.T("report.title") + "\n\n")
+	}
 
 	// 基本信息
-	sb.WriteString("### 扫描信息\n")
-	sb.WriteString(fmt.Sprintf("> 目标网站: **%s**\n", report.TargetURL))
-	sb.WriteString(fmt.Sprintf("> 关键词: `%s`\n", strings.Join(report.Keywords, "`, `")))
-	sb.WriteString(fmt.Sprintf("> 扫描时间: %s\n", report.StartTime))
-	sb.WriteString(fmt.Sprintf("> 耗时: %s\n\n", report.Duration))
+	sb.WriteString("### " + p.T("section.scan_info") + "\n")
+	sb.WriteString(fmt.Sprintf("> %s: **%s**\n", p.T("label.target"), report.TargetURL))
+	sb.WriteString(fmt.Sprintf("> %s: `%s`\n", p.T("label.keywords"), strings.Join(report.Keywords, "`, `")))
+	sb.WriteString(fmt.Sprintf("> %s: %s\n", p.T("label.scan_time"), p.ReportTime(report.StartTime)))
+	sb.WriteString(fmt.Sprintf("> %s: %s\n\n", p.T("label.duration"), p.Duration(report.Duration)))
 
 	// 统计信息
-	sb.WriteString("### 统计摘要\n")
+	sb.WriteString("### " + p.T("section.summary") + "\n")
 
 	// 根据匹配结果设置状态颜色
 	if report.MatchPages > 0 {
-		sb.WriteString(fmt.Sprintf("> <font color=\"warning\">发现 %d 个页面包含关键词</font>\n", report.MatchPages))
+		sb.WriteString(fmt.Sprintf("> <font color=\"warning\">%s</font>\n", p.T("notify.match_pages", report.MatchPages)))
 	} else {
-		sb.WriteString("> <font color=\"info\">未发现包含关键词的页面</font>\n")
+		sb.WriteString(fmt.Sprintf("> <font color=\"info\">%s</font>\n", p.T("notify.no_match")))
 	}
 
-	sb.WriteString(fmt.Sprintf("> 扫描页面总数: **%d**\n", report.TotalPages))
-	sb.WriteString(fmt.Sprintf("> 匹配页面数: **%d**\n", report.MatchPages))
+	sb.WriteString(fmt.Sprintf("> %s: **%d**\n", p.T("label.total_pages"), report.TotalPages))
+	sb.WriteString(fmt.Sprintf("> %s: **%d**\n", p.T("label.match_pages"), report.MatchPages))
 	if report.ErrorCount > 0 {
-		sb.WriteString(fmt.Sprintf("> <font color=\"warning\">错误数: %d</font>\n", report.ErrorCount))
+		sb.WriteString(fmt.Sprintf("> <font color=\"warning\">%s: %d</font>\n", p.T("label.error_count"), report.ErrorCount))
+	}
+	if len(report.Suppressed) > 0 {
+		sb.WriteString(fmt.Sprintf("> %s\n", p.T("notify.suppressed", report.SuppressedCount())))
 	}
 	sb.WriteString("\n")
 
+	// 与上一次扫描相比的变化
+	if diff := report.Diff; diff != nil {
+		sb.WriteString("### " + p.T("section.diff") + "\n")
+		sb.WriteString(fmt.Sprintf("> %s: %s\n", p.T("label.previous_scan"), p.ReportTime(diff.PreviousStartTime)))
+		if len(diff.NewHits) > 0 {
+			sb.WriteString(fmt.Sprintf("> <font color=\"warning\">%s: %s</font>\n", p.T("label.new_hits"), p.T("count.places", len(diff.NewHits))))
+		} else {
+			sb.WriteString(fmt.Sprintf("> <font color=\"info\">%s</font>\n", p.T("notify.no_new_hits")))
+		}
+		sb.WriteString(fmt.Sprintf("> %s\n", p.T("notify.resolved_changed", len(diff.ResolvedHits), len(diff.ChangedHits))))
+		for i, change := range diff.NewHits {
+			if i == 5 {
+				sb.WriteString(fmt.Sprintf("> … %s\n", p.T("notify.total_new_hits", len(diff.NewHits))))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("> - [%s](%s) `%s` %s\n", truncateURL(change.URL, 50), change.URL, change.Keyword, p.T("count.times", change.Current)))
+		}
+		for _, finding := range diff.NewFindings {
+			if finding.Severity == model.SeverityInfo {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("> <font color=\"warning\">%s [%s]</font> %s %s\n", p.T("change.new"), finding.Severity, finding.URL, finding.Title))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 相对近期平均值变化明显的指标
+	if trend := report.Trend; trend != nil {
+		trendCount := 0
+		for _, series := range trend.Series {
+			if !series.Significant {
+				continue
+			}
+			if trendCount == 0 {
+				sb.WriteString("### " + p.T("section.trend") + "\n")
+			}
+			trendCount++
+			// 上升以 warning 颜色提示，下降以 info 颜色提示
+			key, color := "notify.trend_up", "warning"
+			if series.Percent < 0 {
+				key, color = "notify.trend_down", "info"
+			}
+			sb.WriteString(fmt.Sprintf("> <font color=\"%s\">%s</font>\n", color, p.T(key,
+				p.TrendLabel(series), fmt.Sprintf("%.0f%%", math.Abs(series.Percent)), trend.AverageDays,
+				p.TrendValue(series.Metric, series.Average), p.TrendValue(series.Metric, series.Current))))
+		}
+		if trendCount > 0 {
+			sb.WriteString("\n")
+		}
+	}
+
+	// 附加发现（只展示 info 以上级别）
+	findingCount := 0
+	for _, finding := range report.Findings {
+		if finding.Severity == model.SeverityInfo {
+			continue
+		}
+		if findingCount == 0 {
+			sb.WriteString("### " + p.T("section.findings") + "\n")
+		}
+		findingCount++
+		sb.WriteString(fmt.Sprintf("> <font color=\"warning\">[%s]</font> %s %s\n", finding.Severity, finding.URL, finding.Title))
+		// 敏感数据只展示脱敏后的值
+		if finding.Type == model.FindingTypeSensitive {
+			sb.WriteString(fmt.Sprintf("> `%s`\n", finding.Detail))
+		}
+	}
+	if findingCount > 0 {
+		sb.WriteString("\n")
+	}
+
 	// 匹配结果摘要（最多显示 5 条）
 	if len(report.Results) > 0 {
-		sb.WriteString("### 匹配结果 TOP5\n")
+		sb.WriteString("### " + p.T("notify.top_results") + "\n")
 		displayCount := len(report.Results)
 		if displayCount > 5 {
 			displayCount = 5
@@ -105,18 +196,18 @@ func (n *notifier) formatMarkdownContent(report *model.ScanReport, filePath stri
 
 		for i := 0; i < displayCount; i++ {
 			result := report.Results[i]
-			sb.WriteString(fmt.Sprintf("%d. [%s](%s) - 命中 **%d** 次\n",
-				i+1, truncateURL(result.URL, 50), result.URL, result.TotalCount))
+			sb.WriteString(fmt.Sprintf("%d. [%s](%s) - %s\n",
+				i+1, truncateURL(result.URL, 50), result.URL, p.T("notify.hit_times", result.TotalCount)))
 		}
 
 		if len(report.Results) > 5 {
-			sb.WriteString(fmt.Sprintf("\n> 更多结果请查看完整报告（共 %d 条）\n", len(report.Results)))
+			sb.WriteString(fmt.Sprintf("\n> %s\n", p.T("notify.more_results", len(report.Results))))
 		}
 		sb.WriteString("\n")
 	}
 
 	// 报告文件路径
-	sb.WriteString(fmt.Sprintf("📄 报告文件: `%s`", filePath))
+	sb.WriteString(fmt.Sprintf("📄 %s: `%s`", p.T("notify.report_files"), strings.Join(filePaths, "`, `")))
 
 	return sb.String()
 }
@@ -0,0 +1,116 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// csvHeader CSV 报告的列
+var csvHeader = []string{
+	"url", "depth", "keyword", "variant", "distance", "location", "element",
+	"offset", "hidden", "hidden_reason", "snippet_hash", "snippet", "change",
+}
+
+// csvFormatter 每行一次命中的 CSV
+type csvFormatter struct{}
+
+func (f *csvFormatter) Name() string {
+	return FormatCSV
+}
+
+func (f *csvFormatter) Extension() string {
+	return "csv"
+}
+
+func (f *csvFormatter) Format(w io.Writer, report *model.ScanReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	changes := pairChanges(report)
+	rows := make([][]string, 0)
+	for _, result := range report.Results {
+		rows = append(rows, csvRows(result, changes)...)
+	}
+	if report.Diff != nil {
+		rows = append(rows, csvDiffRows(report.Diff)...)
+	}
+	for _, row := range rows {
+		if err := writer.Write(csvCells(row)); err != nil {
+			return fmt.Errorf("failed to write csv row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvRows 生成页面的命中行，没有命中明细的页面按关键词各输出一行；change 列为该页面关键词相对上一次扫描的变化
+func csvRows(result *model.ScanResult, changes map[[2]string]string) [][]string {
+	depth := strconv.Itoa(result.Depth)
+	rows := make([][]string, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		rows = append(rows, []string{
+			result.URL,
+			depth,
+			hit.Keyword,
+			hit.Variant,
+			strconv.Itoa(hit.Distance),
+			hit.Location,
+			hit.Element,
+			strconv.Itoa(hit.Offset),
+			strconv.FormatBool(hit.Hidden),
+			hit.HiddenReason,
+			hit.SnippetHash,
+			hit.Snippet,
+			changes[[2]string{result.URL, hit.Keyword}],
+		})
+	}
+	if len(rows) > 0 {
+		return rows
+	}
+
+	keywords := make([]string, 0, len(result.KeywordCounts))
+	for keyword := range result.KeywordCounts {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	for _, keyword := range keywords {
+		rows = append(rows, []string{result.URL, depth, keyword, "", "", "", "", "", "", "", "", "", changes[[2]string{result.URL, keyword}]})
+	}
+	return rows
+}
+
+// csvLocationFinding 新增附加发现行的 location 列取值
+const csvLocationFinding = "finding"
+
+// csvDiffRows 生成本次扫描中已不存在的命中与新出现的附加发现的行：已消失的命中按页面与关键词各一行，change 列为 resolved；
+// 附加发现的 location 列为 finding，element 列为发现类型，snippet 列为标题，change 列为 new
+func csvDiffRows(diff *model.ScanDiff) [][]string {
+	rows := make([][]string, 0, len(diff.ResolvedHits)+len(diff.NewFindings))
+	for _, change := range diff.ResolvedHits {
+		rows = append(rows, []string{change.URL, "", change.Keyword, "", "", "", "", "", "", "", "", "", model.ChangeResolved})
+	}
+	for _, finding := range diff.NewFindings {
+		rows = append(rows, []string{finding.URL, "", "", "", "", csvLocationFinding, finding.Type, "", "", "", "", finding.Title, model.ChangeNew})
+	}
+	return rows
+}
+
+// csvCells 转义可能被电子表格当作公式的单元格：以 = + - @ 制表符或回车开头的值前加 '，
+// URL、片段等内容来自被扫描的页面，打开 CSV 时不能执行其中的公式
+func csvCells(row []string) []string {
+	for i, cell := range row {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			row[i] = "'" + cell
+		}
+	}
+	return row
+}
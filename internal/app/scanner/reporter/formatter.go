@@ -0,0 +1,17 @@
+package reporter
+
+import (
+	"io"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// Formatter 报告格式化器接口，每种输出格式对应一个实现
+type Formatter interface {
+	// Name 格式名称，对应配置 output.formats 中的取值
+	Name() string
+	// Extension 报告文件扩展名（不含点）
+	Extension() string
+	// Format 将扫描报告写入 w
+	Format(w io.Writer, report *model.ScanReport) error
+}
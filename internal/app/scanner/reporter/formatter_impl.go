@@ -0,0 +1,53 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
+)
+
+// 内置报告格式
+const (
+	FormatTxt      = "txt"
+	FormatJSON     = "json"
+	FormatJSONL    = "jsonl"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatXLSX     = "xlsx"
+)
+
+// registry 内置格式化器构造函数，只在初始化时写入，p 决定报告的语言与时间格式
+var registry = map[string]func(p *i18n.Printer) Formatter{
+	FormatTxt:      func(p *i18n.Printer) Formatter { return &txtFormatter{p: p} },
+	FormatJSON:     func(p *i18n.Printer) Formatter { return &jsonFormatter{} },
+	FormatJSONL:    func(p *i18n.Printer) Formatter { return &jsonlFormatter{} },
+	FormatCSV:      func(p *i18n.Printer) Formatter { return &csvFormatter{} },
+	FormatMarkdown: func(p *i18n.Printer) Formatter { return &markdownFormatter{p: p} },
+	FormatHTML:     func(p *i18n.Printer) Formatter { return &htmlFormatter{p: p} },
+	FormatXLSX:     func(p *i18n.Printer) Formatter { return &xlsxFormatter{p: p} },
+}
+
+// NewFormatters 按名称创建格式化器，未配置任何格式时默认生成 txt 报告，名称未知时返回错误
+func NewFormatters(names []string, p *i18n.Printer) ([]Formatter, error) {
+	formatters := make([]Formatter, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		newFormatter, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown report format: %s", name)
+		}
+		formatters = append(formatters, newFormatter(p))
+	}
+	if len(formatters) == 0 {
+		formatters = append(formatters, registry[FormatTxt](p))
+	}
+	return formatters, nil
+}
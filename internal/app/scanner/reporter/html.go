@@ -0,0 +1,266 @@
+package reporter
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+//go:embed templates/report.html
+var htmlTemplateText string
+
+// htmlTemplate 单文件 HTML 报告模板，样式与脚本全部内联；t 与 time 在生成报告时按语言替换
+var htmlTemplate = template.Must(template.New("report").Funcs(htmlFuncs(i18n.Default())).Parse(htmlTemplateText))
+
+// severityOrder 附加发现严重程度的展示顺序
+var severityOrder = []string{
+	model.SeverityCritical,
+	model.SeverityHigh,
+	model.SeverityMedium,
+	model.SeverityLow,
+	model.SeverityInfo,
+}
+
+// htmlFormatter 可离线查看的单文件 HTML 报告
+type htmlFormatter struct {
+	p *i18n.Printer
+}
+
+// htmlReport HTML 模板使用的报告数据
+type htmlReport struct {
+	*model.ScanReport
+	Locale          string
+	SuppressedCount int
+	FindingCount    int
+	KeywordStats    []*htmlBar
+	SeverityStats   []*htmlBar
+	TrendSummary    string
+	TrendRows       []*htmlTrendRow
+	Pages           []*htmlPage
+}
+
+// htmlTrendRow 统计走势表格中的一个指标
+type htmlTrendRow struct {
+	*model.TrendSeries
+	Label      string
+	Points     string // SVG 折线的坐标
+	LastX      float64
+	LastY      float64
+	CurrentStr string
+	AverageStr string
+	PercentStr string
+}
+
+// 走势图尺寸
+const (
+	sparkWidth  = 120
+	sparkHeight = 24
+)
+
+// htmlBar 统计图中的一个条目
+type htmlBar struct {
+	Label   string
+	Count   int
+	Percent int // 相对最大值的百分比，用于条形宽度
+}
+
+// htmlPage 结果表格中的一个页面
+type htmlPage struct {
+	*model.ScanResult
+	Rank     int
+	Counts   []*htmlBar
+	Variants []string
+	Snippets []*htmlSnippet
+}
+
+// htmlSnippet 高亮关键词后的命中片段
+type htmlSnippet struct {
+	*model.KeywordHit
+	Segments []*htmlSegment
+}
+
+// htmlSegment 片段中的一段文本，Mark 为 true 时高亮显示
+type htmlSegment struct {
+	Text string
+	Mark bool
+}
+
+func (f *htmlFormatter) Name() string {
+	return FormatHTML
+}
+
+func (f *htmlFormatter) Extension() string {
+	return "html"
+}
+
+func (f *htmlFormatter) Format(w io.Writer, report *model.ScanReport) error {
+	tmpl, err := htmlTemplate.Clone()
+	if err != nil {
+		return fmt.Errorf("failed to clone html template: %w", err)
+	}
+	if err := tmpl.Funcs(htmlFuncs(f.p)).Execute(w, newHTMLReport(f.p, report)); err != nil {
+		return fmt.Errorf("failed to render html report: %w", err)
+	}
+	return nil
+}
+
+func htmlFuncs(p *i18n.Printer) template.FuncMap {
+	return template.FuncMap{
+		"t":        p.T,
+		"time":     p.ReportTime,
+		"duration": p.Duration,
+	}
+}
+
+func newHTMLReport(p *i18n.Printer, report *model.ScanReport) *htmlReport {
+	data := &htmlReport{
+		ScanReport:      report,
+		Locale:          p.Locale(),
+		SuppressedCount: report.SuppressedCount(),
+		Pages:           make([]*htmlPage, 0, len(report.Results)),
+	}
+
+	keywordCounts := make(map[string]int)
+	for i, result := range sortedResults(report) {
+		for keyword, count := range result.KeywordCounts {
+			keywordCounts[keyword] += count
+		}
+		page := &htmlPage{
+			ScanResult: result,
+			Rank:       i + 1,
+			Counts:     toBars(result.KeywordCounts, nil),
+			Variants:   hitVariants(p, result),
+			Snippets:   make([]*htmlSnippet, 0, len(result.Hits)),
+		}
+		for _, hit := range result.Hits {
+			page.Snippets = append(page.Snippets, &htmlSnippet{
+				KeywordHit: hit,
+				Segments:   highlight(hit.Snippet, hit.Keyword, hit.Variant),
+			})
+		}
+		data.Pages = append(data.Pages, page)
+	}
+	data.KeywordStats = toBars(keywordCounts, nil)
+
+	// 数量与分布和附加发现表格保持一致，包含 info 级别的发现
+	severityCounts := make(map[string]int)
+	for _, finding := range report.Findings {
+		severityCounts[finding.Severity]++
+	}
+	data.FindingCount = len(report.Findings)
+	data.SeverityStats = toBars(severityCounts, severityOrder)
+
+	if trend := report.Trend; trend != nil {
+		data.TrendSummary = trendSummary(p, trend)
+		for _, series := range trend.Series {
+			row := &htmlTrendRow{
+				TrendSeries: series,
+				Label:       p.TrendLabel(series),
+				CurrentStr:  p.TrendValue(series.Metric, series.Current),
+				AverageStr:  trendAverage(p, series),
+				PercentStr:  trendPercent(series),
+			}
+			row.Points, row.LastX, row.LastY = sparkPoints(series.Values)
+			data.TrendRows = append(data.TrendRows, row)
+		}
+	}
+
+	return data
+}
+
+// sparkPoints 计算走势折线的坐标，留出 2 像素边距，返回最后一个点的位置用于标记本次扫描
+func sparkPoints(values []float64) (points string, lastX, lastY float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+	coords := make([]string, 0, len(values))
+	for i, v := range values {
+		lastX = sparkWidth / 2
+		if len(values) > 1 {
+			lastX = 2 + float64(i)*(sparkWidth-4)/float64(len(values)-1)
+		}
+		lastY = sparkHeight / 2
+		if high > low {
+			lastY = sparkHeight - 2 - (v-low)/(high-low)*(sparkHeight-4)
+		}
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", lastX, lastY))
+	}
+	return strings.Join(coords, " "), lastX, lastY
+}
+
+// toBars 将计数转换为统计条目，order 为空时按数量降序排列
+func toBars(counts map[string]int, order []string) []*htmlBar {
+	labels := order
+	if len(labels) == 0 {
+		labels = make([]string, 0, len(counts))
+		for label := range counts {
+			labels = append(labels, label)
+		}
+		sort.Slice(labels, func(i, j int) bool {
+			if counts[labels[i]] != counts[labels[j]] {
+				return counts[labels[i]] > counts[labels[j]]
+			}
+			return labels[i] < labels[j]
+		})
+	}
+
+	maxCount := 0
+	for _, count := range counts {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	bars := make([]*htmlBar, 0, len(labels))
+	for _, label := range labels {
+		count := counts[label]
+		if count == 0 {
+			continue
+		}
+		bars = append(bars, &htmlBar{Label: label, Count: count, Percent: count * 100 / maxCount})
+	}
+	return bars
+}
+
+// highlight 按关键词及命中写法切分片段，匹配时忽略大小写
+func highlight(snippet string, terms ...string) []*htmlSegment {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	if len(quoted) == 0 || snippet == "" {
+		return []*htmlSegment{{Text: snippet}}
+	}
+	// 较长的写法优先，避免只高亮其中一部分
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	re, err := regexp.Compile(`(?i)` + strings.Join(quoted, "|"))
+	if err != nil {
+		return []*htmlSegment{{Text: snippet}}
+	}
+
+	segments := make([]*htmlSegment, 0)
+	last := 0
+	for _, loc := range re.FindAllStringIndex(snippet, -1) {
+		if loc[0] > last {
+			segments = append(segments, &htmlSegment{Text: snippet[last:loc[0]]})
+		}
+		segments = append(segments, &htmlSegment{Text: snippet[loc[0]:loc[1]], Mark: true})
+		last = loc[1]
+	}
+	if last < len(snippet) {
+		segments = append(segments, &htmlSegment{Text: snippet[last:]})
+	}
+	return segments
+}
@@ -0,0 +1,62 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// jsonFormatter 完整的扫描报告 JSON
+type jsonFormatter struct{}
+
+func (f *jsonFormatter) Name() string {
+	return FormatJSON
+}
+
+func (f *jsonFormatter) Extension() string {
+	return "json"
+}
+
+func (f *jsonFormatter) Format(w io.Writer, report *model.ScanReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	return nil
+}
+
+// jsonlFormatter 每行一个匹配页面的 JSON Lines，与上一次扫描比较时最后追加一行变化记录
+type jsonlFormatter struct{}
+
+// jsonlDiffRecord JSON Lines 报告中的变化记录，以 diff 字段与页面记录区分
+type jsonlDiffRecord struct {
+	Diff *model.ScanDiff `json:"diff"`
+}
+
+func (f *jsonlFormatter) Name() string {
+	return FormatJSONL
+}
+
+func (f *jsonlFormatter) Extension() string {
+	return "jsonl"
+}
+
+func (f *jsonlFormatter) Format(w io.Writer, report *model.ScanReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, result := range report.Results {
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to encode result %s: %w", result.URL, err)
+		}
+	}
+	if report.Diff != nil {
+		if err := encoder.Encode(&jsonlDiffRecord{Diff: report.Diff}); err != nil {
+			return fmt.Errorf("failed to encode diff: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// markdownFormatter Markdown 报告，便于粘贴到工单或文档
+type markdownFormatter struct {
+	p *i18n.Printer
+}
+
+func (f *markdownFormatter) Name() string {
+	return FormatMarkdown
+}
+
+func (f *markdownFormatter) Extension() string {
+	return "md"
+}
+
+func (f *markdownFormatter) Format(w io.Writer, report *model.ScanReport) error {
+	p := f.p
+	var sb strings.Builder
+
+	sb.WriteString("# " + p.T("report.title") + "\n\n")
+
+	// 基本信息
+	sb.WriteString("## " + p.T("section.scan_info") + "\n\n")
+	sb.WriteString(mdHeader(p.T("label.item"), p.T("label.value")))
+	sb.WriteString(fmt.Sprintf("| %s | %s |\n", p.T("label.target"), mdCell(report.TargetURL)))
+	sb.WriteString(fmt.Sprintf("| %s | %s |\n", p.T("label.keywords"), mdCell(strings.Join(report.Keywords, ", "))))
+	sb.WriteString(fmt.Sprintf("| %s | %s |\n", p.T("label.start_time"), p.ReportTime(report.StartTime)))
+	sb.WriteString(fmt.Sprintf("| %s | %s |\n", p.T("label.end_time"), p.ReportTime(report.EndTime)))
+	sb.WriteString(fmt.Sprintf("| %s | %s |\n", p.T("label.duration"), p.Duration(report.Duration)))
+	if report.ScanMode == model.ScanModeOffline {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", p.T("label.scan_mode"), p.T("scan_mode.offline_source", mdCell(report.WARCFile))))
+	} else if report.WARCFile != "" {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", p.T("label.warc"), mdCell(report.WARCFile)))
+	}
+	if report.EvidenceDir != "" {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", p.T("label.evidence"), mdCell(report.EvidenceDir)))
+	}
+	sb.WriteString("\n")
+
+	// 统计信息
+	sb.WriteString("## " + p.T("section.summary") + "\n\n")
+	sb.WriteString(fmt.Sprintf("- %s: **%d**\n", p.T("label.total_pages"), report.TotalPages))
+	sb.WriteString(fmt.Sprintf("- %s: **%d**\n", p.T("label.match_pages"), report.MatchPages))
+	sb.WriteString(fmt.Sprintf("- %s: **%d**\n", p.T("label.error_count"), report.ErrorCount))
+	if len(report.Suppressed) > 0 {
+		sb.WriteString(fmt.Sprintf("- %s: **%d**\n", p.T("label.suppressed_count"), report.SuppressedCount()))
+	}
+	sb.WriteString("\n")
+
+	// 与上一次扫描相比
+	if diff := report.Diff; diff != nil {
+		sb.WriteString("## " + p.T("section.diff") + "\n\n")
+		sb.WriteString(fmt.Sprintf("%s: %s\n\n", p.T("label.previous_scan"), p.ReportTime(diff.PreviousStartTime)))
+		sb.WriteString(fmt.Sprintf("- %s: **%d**\n", p.T("label.new_hits"), len(diff.NewHits)))
+		sb.WriteString(fmt.Sprintf("- %s: **%d**\n", p.T("label.resolved_hits"), len(diff.ResolvedHits)))
+		sb.WriteString(fmt.Sprintf("- %s: **%d**\n", p.T("label.changed_hits"), len(diff.ChangedHits)))
+		sb.WriteString(fmt.Sprintf("- %s: **%d**\n", p.T("label.persisting_hits"), diff.PersistingCount))
+		sb.WriteString(fmt.Sprintf("- %s: **%d**\n\n", p.T("label.new_findings"), len(diff.NewFindings)))
+		changes := make([][]string, 0, len(diff.NewHits)+len(diff.ChangedHits)+len(diff.ResolvedHits))
+		for _, change := range diff.NewHits {
+			changes = append(changes, []string{p.T("change.new"), change.URL, change.Keyword, "0", fmt.Sprint(change.Current)})
+		}
+		for _, change := range diff.ChangedHits {
+			changes = append(changes, []string{p.T("label.changed_hits"), change.URL, change.Keyword, fmt.Sprint(change.Previous), fmt.Sprint(change.Current)})
+		}
+		for _, change := range diff.ResolvedHits {
+			changes = append(changes, []string{p.T("label.resolved_hits"), change.URL, change.Keyword, fmt.Sprint(change.Previous), "0"})
+		}
+		if len(changes) > 0 {
+			sb.WriteString(mdHeader(p.T("label.change"), "URL", p.T("label.keyword"), p.T("label.previous_count"), p.T("label.current_count")))
+			for _, row := range changes {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", row[0], mdCell(row[1]), mdCell(row[2]), row[3], row[4]))
+			}
+			sb.WriteString("\n")
+		}
+		for _, finding := range diff.NewFindings {
+			sb.WriteString(fmt.Sprintf("- %s [%s] %s %s\n", p.T("label.new_finding"), finding.Severity, mdCell(finding.URL), mdCell(finding.Title)))
+		}
+		if len(diff.NewFindings) > 0 {
+			sb.WriteString("\n")
+		}
+	}
+
+	// 统计走势，变化达到阈值的指标加粗
+	if trend := report.Trend; trend != nil {
+		sb.WriteString("## " + p.T("section.trend") + "\n\n")
+		sb.WriteString(trendSummary(p, trend) + "\n\n")
+		sb.WriteString(mdHeader(p.T("label.metric"), p.T("label.trend"), p.T("label.current"), p.T("label.average", trend.AverageDays), p.T("label.change")))
+		for _, series := range trend.Series {
+			percent := trendPercent(series)
+			if series.Significant {
+				percent = "**" + percent + "**"
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", mdCell(p.TrendLabel(series)), sparkline(series.Values),
+				p.TrendValue(series.Metric, series.Current), trendAverage(p, series), percent))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 附加发现
+	if len(report.Findings) > 0 {
+		sb.WriteString("## " + p.T("section.findings") + "\n\n")
+		sb.WriteString(mdHeader(p.T("label.severity"), "URL", p.T("label.title"), p.T("label.detail")))
+		for _, finding := range report.Findings {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				finding.Severity, mdCell(finding.URL), mdCell(finding.Title), mdCell(finding.Detail)))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 扫描错误
+	if len(report.Errors) > 0 {
+		sb.WriteString("## " + p.T("section.errors") + "\n\n")
+		sb.WriteString(mdHeader("URL", p.T("label.depth"), p.T("label.error")))
+		for _, scanErr := range report.Errors {
+			sb.WriteString(fmt.Sprintf("| %s | %d | %s |\n", mdCell(scanErr.URL), scanErr.Depth, mdCell(scanErr.Error)))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 已豁免命中
+	if len(report.Suppressed) > 0 {
+		sb.WriteString("## " + p.T("section.suppressed") + "\n\n")
+		sb.WriteString(mdHeader("URL", p.T("label.keyword"), p.T("label.count"), p.T("label.snippet_hash"), p.T("label.reason"), p.T("label.expires")))
+		for _, s := range report.Suppressed {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %d | %s | %s | %s |\n",
+				mdCell(s.URL), mdCell(s.Keyword), s.Count, s.SnippetHash, mdCell(s.Reason), s.Expires))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 匹配结果
+	sb.WriteString("## " + p.T("section.results") + "\n\n")
+	if len(report.Results) == 0 {
+		sb.WriteString(p.T("result.none") + "\n")
+	} else {
+		results := sortedResults(report)
+		sb.WriteString(mdHeader("#", "URL", p.T("label.depth"), p.T("label.occurrences"), p.T("label.keyword"), p.T("label.change")))
+		for i, result := range results {
+			sb.WriteString(fmt.Sprintf("| %d | %s | %d | %d | %s | %s |\n",
+				i+1, mdCell(result.URL), result.Depth, result.TotalCount, mdCell(strings.Join(result.Keywords, ", ")), changeLabel(p, result.Change)))
+		}
+		sb.WriteString("\n")
+
+		for i, result := range results {
+			sb.WriteString(fmt.Sprintf("### %d. %s\n\n", i+1, mdCell(result.URL)))
+			keywords := make([]string, 0, len(result.KeywordCounts))
+			for keyword := range result.KeywordCounts {
+				keywords = append(keywords, keyword)
+			}
+			sort.Strings(keywords)
+			for _, keyword := range keywords {
+				sb.WriteString(fmt.Sprintf("- %s: %s\n", mdCell(keyword), p.T("count.times", result.KeywordCounts[keyword])))
+			}
+			if variants := hitVariants(p, result); len(variants) > 0 {
+				sb.WriteString(fmt.Sprintf("- %s: %s\n", p.T("label.variants"), mdCell(strings.Join(variants, ", "))))
+			}
+			if result.HiddenCount > 0 {
+				sb.WriteString(fmt.Sprintf("- %s: %s\n", p.T("label.hidden_hits"), p.T("count.times", result.HiddenCount)))
+			}
+			for _, hit := range result.Sensitive {
+				sb.WriteString(fmt.Sprintf("- %s [%s]: `%s`\n", p.T("label.sensitive"), hit.Detector, hit.Value))
+			}
+			if len(result.Hits) > 0 {
+				sb.WriteString("\n" + mdHeader(p.T("label.keyword"), p.T("label.location"), p.T("label.snippet"), p.T("label.snippet_hash")))
+				for j, hit := range result.Hits {
+					if j == maxReportSnippets {
+						break
+					}
+					location := hit.Location
+					if hit.Hidden {
+						location = p.T("location.hidden", hit.Location, hit.HiddenReason)
+					}
+					sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+						mdCell(hit.Keyword), location, mdCell(hit.Snippet), hit.SnippetHash))
+				}
+				if len(result.Hits) > maxReportSnippets {
+					sb.WriteString("\n" + p.T("result.snippets_limited", len(result.Hits), maxReportSnippets) + "\n")
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// mdHeader 生成表头与分隔行
+func mdHeader(columns ...string) string {
+	separators := make([]string, len(columns))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	return "| " + strings.Join(columns, " | ") + " |\n| " + strings.Join(separators, " | ") + " |\n"
+}
+
+// mdCell 转义表格单元格中的竖线与换行
+func mdCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r", "")
+	return strings.ReplaceAll(s, "\n", " ")
+}
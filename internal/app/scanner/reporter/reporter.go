@@ -8,6 +8,7 @@ import (
 
 // Reporter 报告生成器接口
 type Reporter interface {
-	// GenerateReport 生成扫描报告并保存到文件
-	GenerateReport(ctx context.Context, report *model.ScanReport) (filePath string, err error)
+	// GenerateReport 按配置的格式生成扫描报告并保存到文件，返回生成的全部文件路径；
+	// 部分格式写入失败时只记录日志，全部失败时返回错误
+	GenerateReport(ctx context.Context, report *model.ScanReport) (filePaths []string, err error)
 }
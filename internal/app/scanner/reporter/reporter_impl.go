@@ -2,7 +2,9 @@ package reporter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
 	"github.com/gw-gong/key-spy/internal/pkg/model"
 
 	"github.com/gw-gong/gwkit-go/log"
@@ -25,87 +28,189 @@ func NewReporter(cfg *localcfg.Config) Reporter {
 	}
 }
 
-func (r *reporter) GenerateReport(ctx context.Context, report *model.ScanReport) (filePath string, err error) {
+func (r *reporter) GenerateReport(ctx context.Context, report *model.ScanReport) (filePaths []string, err error) {
+	// 报告使用扫描目标配置的语言与时区
+	p, err := i18n.NewPrinter(r.cfg.Scanner.Locale.Resolve(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create printer: %w", err)
+	}
+
+	formatters, err := NewFormatters(r.cfg.Output.Formats, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report formatters: %w", err)
+	}
+
 	// 确保输出目录存在
 	if err := os.MkdirAll(r.cfg.Output.Dir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create output directory: %w", err)
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// 生成文件名
-	timestamp := time.Now().Format("20060102_150405")
-	fileName := fmt.Sprintf("%s_%s.txt", r.cfg.Output.FilePrefix, timestamp)
-	filePath = filepath.Join(r.cfg.Output.Dir, fileName)
+	// 同一次扫描的各格式报告共用文件名，时间取扫描开始时间，与 WARC 归档、证据快照一致
+	startTime, err := time.ParseInLocation(i18n.ReportTimeLayout, report.StartTime, time.Local)
+	if err != nil {
+		startTime = time.Now()
+	}
+	baseName := fmt.Sprintf("%s_%s", r.cfg.Output.FilePrefix, startTime.Format("20060102_150405"))
+
+	filePaths = make([]string, 0, len(formatters))
+	for _, formatter := range formatters {
+		filePath := filepath.Join(r.cfg.Output.Dir, baseName+"."+formatter.Extension())
+		// 单个格式失败不影响其他格式的报告
+		if err := writeReport(filePath, formatter, report); err != nil {
+			log.Errorc(ctx, "Failed to write report", log.Str("format", formatter.Name()), log.Err(err))
+			continue
+		}
+		log.Infoc(ctx, "Report generated", log.Str("format", formatter.Name()), log.Str("file_path", filePath))
+		filePaths = append(filePaths, filePath)
+	}
 
-	// 生成报告内容
-	content := r.formatReport(report)
+	// 自定义模板每次都重新读取，出错时只跳过该模板，不影响其他报告
+	templates, err := NewTemplateFormatters(r.cfg.Output.Templates, p)
+	if err != nil {
+		log.Errorc(ctx, "Failed to load report templates", log.Err(err))
+	}
+	for _, formatter := range templates {
+		filePath := filepath.Join(r.cfg.Output.Dir, baseName+"."+formatter.Extension())
+		if err := writeReport(filePath, formatter, report); err != nil {
+			log.Errorc(ctx, "Failed to write template report", log.Str("template", formatter.Name()), log.Err(err))
+			continue
+		}
+		log.Infoc(ctx, "Report generated", log.Str("template", formatter.Name()), log.Str("file_path", filePath))
+		filePaths = append(filePaths, filePath)
+	}
 
-	// 写入文件
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return "", fmt.Errorf("failed to write report file: %w", err)
+	if len(filePaths) == 0 {
+		return nil, errors.New("no report was written")
 	}
+	return filePaths, nil
+}
 
-	log.Infoc(ctx, "Report generated", log.Str("file_path", filePath))
+// writeReport 使用格式化器生成报告文件，失败时删除未写完的文件
+func writeReport(filePath string, formatter Formatter, report *model.ScanReport) (err error) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close report file: %w", closeErr)
+		}
+		if err != nil {
+			os.Remove(filePath)
+		}
+	}()
 
-	return filePath, nil
+	if err := formatter.Format(file, report); err != nil {
+		return err
+	}
+	return nil
 }
 
-func (r *reporter) formatReport(report *model.ScanReport) string {
-	var sb strings.Builder
+// hitVariants 汇总命中明细中出现的繁简、拼音写法及模糊命中
+func hitVariants(p *i18n.Printer, result *model.ScanResult) []string {
+	seen := make(map[string]bool)
+	variants := make([]string, 0)
+	for _, hit := range result.Hits {
+		if hit.Variant == "" {
+			continue
+		}
+		variant := p.T("variant.label", hit.Variant, hit.Keyword)
+		if hit.Distance > 0 {
+			variant = p.T("variant.label_fuzzy", hit.Variant, hit.Keyword, hit.Distance)
+		}
+		if !seen[variant] {
+			seen[variant] = true
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
 
-	// 报告头部
-	sb.WriteString("=" + strings.Repeat("=", 79) + "\n")
-	sb.WriteString("                           KEY-SPY 扫描报告\n")
-	sb.WriteString("=" + strings.Repeat("=", 79) + "\n\n")
-
-	// 基本信息
-	sb.WriteString("【扫描信息】\n")
-	sb.WriteString(fmt.Sprintf("  目标网站: %s\n", report.TargetURL))
-	sb.WriteString(fmt.Sprintf("  搜索关键词: %s\n", strings.Join(report.Keywords, ", ")))
-	sb.WriteString(fmt.Sprintf("  开始时间: %s\n", report.StartTime))
-	sb.WriteString(fmt.Sprintf("  结束时间: %s\n", report.EndTime))
-	sb.WriteString(fmt.Sprintf("  耗时: %s\n", report.Duration))
-	sb.WriteString("\n")
-
-	// 统计信息
-	sb.WriteString("【统计摘要】\n")
-	sb.WriteString(fmt.Sprintf("  扫描页面总数: %d\n", report.TotalPages))
-	sb.WriteString(fmt.Sprintf("  匹配关键词页面数: %d\n", report.MatchPages))
-	sb.WriteString(fmt.Sprintf("  扫描错误数: %d\n", report.ErrorCount))
-	sb.WriteString("\n")
-
-	// 匹配结果
-	if len(report.Results) > 0 {
-		sb.WriteString("-" + strings.Repeat("-", 79) + "\n")
-		sb.WriteString("                           匹配结果详情\n")
-		sb.WriteString("-" + strings.Repeat("-", 79) + "\n\n")
-
-		// 按出现次数排序
-		sortedResults := make([]*model.ScanResult, len(report.Results))
-		copy(sortedResults, report.Results)
-		sort.Slice(sortedResults, func(i, j int) bool {
-			return sortedResults[i].TotalCount > sortedResults[j].TotalCount
-		})
-
-		for i, result := range sortedResults {
-			sb.WriteString(fmt.Sprintf("[%d] URL: %s\n", i+1, result.URL))
-			sb.WriteString(fmt.Sprintf("    页面深度: %d\n", result.Depth))
-			sb.WriteString(fmt.Sprintf("    关键词总出现次数: %d\n", result.TotalCount))
-			sb.WriteString(fmt.Sprintf("    出现的关键词: %s\n", strings.Join(result.Keywords, ", ")))
-			sb.WriteString("    各关键词统计:\n")
-			for keyword, count := range result.KeywordCounts {
-				sb.WriteString(fmt.Sprintf("      - %s: %d 次\n", keyword, count))
-			}
-			sb.WriteString("\n")
+// sortedResults 返回按关键词出现次数降序排列的结果副本
+func sortedResults(report *model.ScanReport) []*model.ScanResult {
+	results := make([]*model.ScanResult, len(report.Results))
+	copy(results, report.Results)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].TotalCount > results[j].TotalCount
+	})
+	return results
+}
+
+// changeLabel 页面变化的说明
+func changeLabel(p *i18n.Printer, change string) string {
+	switch change {
+	case model.ChangeNew:
+		return p.T("change.new")
+	case model.ChangeChanged:
+		return p.T("change.changed")
+	case model.ChangePersisting:
+		return p.T("change.persisting")
+	}
+	return ""
+}
+
+// pairChanges 按页面与关键词查找本次命中相对上一次扫描的变化，未比较时返回 nil
+func pairChanges(report *model.ScanReport) map[[2]string]string {
+	if report.Diff == nil {
+		return nil
+	}
+	changes := make(map[[2]string]string)
+	for _, result := range report.Results {
+		for keyword := range result.KeywordCounts {
+			changes[[2]string{result.URL, keyword}] = model.ChangePersisting
 		}
-	} else {
-		sb.WriteString("【匹配结果】\n")
-		sb.WriteString("  未找到包含关键词的页面。\n\n")
 	}
+	for _, change := range report.Diff.NewHits {
+		changes[[2]string{change.URL, change.Keyword}] = model.ChangeNew
+	}
+	for _, change := range report.Diff.ChangedHits {
+		changes[[2]string{change.URL, change.Keyword}] = model.ChangeChanged
+	}
+	return changes
+}
 
-	// 报告尾部
-	sb.WriteString("=" + strings.Repeat("=", 79) + "\n")
-	sb.WriteString("                           报告结束\n")
-	sb.WriteString("=" + strings.Repeat("=", 79) + "\n")
+// sparkBlocks 文本走势图使用的字符，从低到高
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
 
+// sparkline 以字符绘制走势，所有取值相同时绘制为最低一档
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+	var sb strings.Builder
+	for _, v := range values {
+		level := 0
+		if high > low {
+			level = int((v - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		sb.WriteRune(sparkBlocks[level])
+	}
 	return sb.String()
 }
+
+// trendSummary 走势的统计范围说明
+func trendSummary(p *i18n.Printer, trend *model.ScanTrend) string {
+	first, last := trend.Points[0], trend.Points[len(trend.Points)-1]
+	return p.T("trend.summary", trend.Days, len(trend.Points), p.ReportTime(first.StartTime), p.ReportTime(last.StartTime))
+}
+
+// trendAverage 平均值统计周期内此前扫描的平均值，没有此前的扫描时为 "-"
+func trendAverage(p *i18n.Printer, series *model.TrendSeries) string {
+	if !series.HasAverage {
+		return "-"
+	}
+	return p.TrendValue(series.Metric, series.Average)
+}
+
+// trendPercent 本次相对平均值的变化百分比，无法计算时为 "-"
+func trendPercent(series *model.TrendSeries) string {
+	if !series.HasAverage || series.Average == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.0f%%", series.Percent)
+}
@@ -0,0 +1,230 @@
+package reporter
+
+import (
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// 模板引擎
+const (
+	EngineText = "text"
+	EngineHTML = "html"
+)
+
+// templateNameRe 模板名称与扩展名会出现在报告文件名中
+var templateNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// TemplateData 用户模板的数据，可直接访问 model.ScanReport 的全部字段
+type TemplateData struct {
+	*model.ScanReport
+	Locale          string              // 报告语言，如 zh-CN
+	GeneratedAt     time.Time           // 报告生成时间
+	SortedResults   []*model.ScanResult // 按关键词出现次数降序排列的匹配结果
+	HitCount        int                 // 关键词总出现次数
+	SuppressedCount int                 // 被豁免的命中次数
+}
+
+// templateFormatter 使用用户提供的 text/template 或 html/template 文件生成报告
+type templateFormatter struct {
+	p         *i18n.Printer
+	name      string
+	extension string
+	execute   func(w io.Writer, data *TemplateData) error
+}
+
+// NewTemplateFormatters 读取并解析配置中的用户模板，每次生成报告时调用，修改配置或模板文件后无需重启；
+// 无效的模板会被跳过，错误合并后返回
+func NewTemplateFormatters(configs []*localcfg.TemplateConfig, p *i18n.Printer) ([]Formatter, error) {
+	formatters := make([]Formatter, 0, len(configs))
+	seen := make(map[string]bool)
+	var errs []error
+	for _, cfg := range configs {
+		if cfg == nil {
+			continue
+		}
+		if !templateNameRe.MatchString(cfg.Name) {
+			errs = append(errs, fmt.Errorf("invalid template name %q: only letters, digits, '_' and '-' are allowed", cfg.Name))
+			continue
+		}
+		if seen[cfg.Name] {
+			errs = append(errs, fmt.Errorf("duplicate template name: %s", cfg.Name))
+			continue
+		}
+		seen[cfg.Name] = true
+
+		formatter, err := newTemplateFormatter(cfg, p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		formatters = append(formatters, formatter)
+	}
+	return formatters, errors.Join(errs...)
+}
+
+func newTemplateFormatter(cfg *localcfg.TemplateConfig, p *i18n.Printer) (*templateFormatter, error) {
+	content, err := os.ReadFile(cfg.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", cfg.Name, err)
+	}
+
+	// 扩展名默认取模板文件去掉 .tmpl/.tpl 后的扩展名
+	fileName := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(cfg.File), ".tmpl"), ".tpl")
+	fileExt := strings.ToLower(strings.TrimPrefix(filepath.Ext(fileName), "."))
+
+	engine := strings.ToLower(cfg.Engine)
+	if engine == "" {
+		engine = EngineText
+		if fileExt == "html" || fileExt == "htm" {
+			engine = EngineHTML
+		}
+	}
+
+	extension := strings.TrimPrefix(cfg.Extension, ".")
+	if extension == "" {
+		extension = fileExt
+	}
+	if extension == "" {
+		extension = "txt"
+		if engine == EngineHTML {
+			extension = "html"
+		}
+	}
+	// 扩展名同样出现在报告文件名中，不允许包含路径分隔符等字符
+	if !templateNameRe.MatchString(extension) {
+		return nil, fmt.Errorf("invalid extension %q of template %s: only letters, digits, '_' and '-' are allowed", extension, cfg.Name)
+	}
+
+	f := &templateFormatter{
+		p:         p,
+		name:      cfg.Name,
+		extension: cfg.Name + "." + extension,
+	}
+	switch engine {
+	case EngineText:
+		tmpl, err := texttemplate.New(cfg.Name).Funcs(templateFuncs(p, false)).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", cfg.Name, err)
+		}
+		f.execute = func(w io.Writer, data *TemplateData) error { return tmpl.Execute(w, data) }
+	case EngineHTML:
+		tmpl, err := htmltemplate.New(cfg.Name).Funcs(templateFuncs(p, true)).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", cfg.Name, err)
+		}
+		f.execute = func(w io.Writer, data *TemplateData) error { return tmpl.Execute(w, data) }
+	default:
+		return nil, fmt.Errorf("unknown engine of template %s: %s", cfg.Name, cfg.Engine)
+	}
+	return f, nil
+}
+
+func (f *templateFormatter) Name() string {
+	return f.name
+}
+
+// Extension 报告文件名为 {前缀}_{时间}.{模板名称}.{扩展名}，避免与内置格式冲突
+func (f *templateFormatter) Extension() string {
+	return f.extension
+}
+
+func (f *templateFormatter) Format(w io.Writer, report *model.ScanReport) error {
+	data := &TemplateData{
+		ScanReport:      report,
+		Locale:          f.p.Locale(),
+		GeneratedAt:     time.Now(),
+		SortedResults:   sortedResults(report),
+		SuppressedCount: report.SuppressedCount(),
+	}
+	for _, result := range report.Results {
+		data.HitCount += result.TotalCount
+	}
+
+	if err := f.execute(w, data); err != nil {
+		return fmt.Errorf("failed to execute template %s: %w", f.name, err)
+	}
+	return nil
+}
+
+// templateFuncs 模板辅助函数，html 模板中 highlight 返回已转义的 HTML
+func templateFuncs(p *i18n.Printer, html bool) map[string]interface{} {
+	funcs := map[string]interface{}{
+		"truncate":       truncate,
+		"sortBySeverity": sortBySeverity,
+		"formatDuration": p.Duration,
+		"formatTime":     p.ReportTime,
+		"t":              p.T,
+		"join":           strings.Join,
+		"changeLabel":    func(change string) string { return changeLabel(p, change) },
+	}
+	if html {
+		funcs["highlight"] = func(snippet string, terms ...string) htmltemplate.HTML {
+			var sb strings.Builder
+			for _, segment := range highlight(snippet, terms...) {
+				text := htmltemplate.HTMLEscapeString(segment.Text)
+				if segment.Mark {
+					text = "<mark>" + text + "</mark>"
+				}
+				sb.WriteString(text)
+			}
+			return htmltemplate.HTML(sb.String())
+		}
+	} else {
+		funcs["highlight"] = func(snippet string, terms ...string) string {
+			var sb strings.Builder
+			for _, segment := range highlight(snippet, terms...) {
+				if segment.Mark {
+					sb.WriteString("【" + segment.Text + "】")
+				} else {
+					sb.WriteString(segment.Text)
+				}
+			}
+			return sb.String()
+		}
+	}
+	return funcs
+}
+
+// truncate 按字符截断文本，超出部分以省略号代替
+func truncate(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
+
+// sortBySeverity 按严重程度从高到低排列附加发现，不修改原切片
+func sortBySeverity(findings []*model.Finding) []*model.Finding {
+	rank := make(map[string]int, len(severityOrder))
+	for i, severity := range severityOrder {
+		rank[severity] = i
+	}
+	sorted := make([]*model.Finding, len(findings))
+	copy(sorted, findings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, ok := rank[sorted[i].Severity]
+		if !ok {
+			ri = len(severityOrder)
+		}
+		rj, ok := rank[sorted[j].Severity]
+		if !ok {
+			rj = len(severityOrder)
+		}
+		return ri < rj
+	})
+	return sorted
+}
@@ -0,0 +1,216 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// maxReportSnippets 每个页面在报告中列出的命中片段数
+const maxReportSnippets = 5
+
+// txtFormatter 纯文本报告
+type txtFormatter struct {
+	p *i18n.Printer
+}
+
+func (f *txtFormatter) Name() string {
+	return FormatTxt
+}
+
+func (f *txtFormatter) Extension() string {
+	return "txt"
+}
+
+// Format 生成纯文本报告
+func (f *txtFormatter) Format(w io.Writer, report *model.ScanReport) error {
+	p := f.p
+	var sb strings.Builder
+
+	// 报告头部
+	sb.WriteString("=" + strings.Repeat("=", 79) + "\n")
+	sb.WriteString("                           " + strings.ToUpper(p.T("report.title")) + "\n")
+	sb.WriteString("=" + strings.Repeat("=", 79) + "\n\n")
+
+	// 基本信息
+	sb.WriteString(p.T("txt.section", p.T("section.scan_info")))
+	sb.WriteString(fmt.Sprintf("  %s: %s\n", p.T("label.target"), report.TargetURL))
+	sb.WriteString(fmt.Sprintf("  %s: %s\n", p.T("label.keywords"), strings.Join(report.Keywords, ", ")))
+	sb.WriteString(fmt.Sprintf("  %s: %s\n", p.T("label.start_time"), p.ReportTime(report.StartTime)))
+	sb.WriteString(fmt.Sprintf("  %s: %s\n", p.T("label.end_time"), p.ReportTime(report.EndTime)))
+	sb.WriteString(fmt.Sprintf("  %s: %s\n", p.T("label.duration"), p.Duration(report.Duration)))
+	if report.ScanMode == model.ScanModeOffline {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", p.T("label.scan_mode"), p.T("scan_mode.offline_detail")))
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", p.T("label.source_archive"), report.WARCFile))
+	} else if report.WARCFile != "" {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", p.T("label.warc"), report.WARCFile))
+	}
+	if report.EvidenceDir != "" {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", p.T("label.evidence"), report.EvidenceDir))
+	}
+	sb.WriteString("\n")
+
+	// 统计信息
+	sb.WriteString(p.T("txt.section", p.T("section.summary")))
+	sb.WriteString(fmt.Sprintf("  %s: %d\n", p.T("label.total_pages"), report.TotalPages))
+	sb.WriteString(fmt.Sprintf("  %s: %d\n", p.T("label.match_pages"), report.MatchPages))
+	sb.WriteString(fmt.Sprintf("  %s: %d\n", p.T("label.error_count"), report.ErrorCount))
+	if len(report.Suppressed) > 0 {
+		sb.WriteString(fmt.Sprintf("  %s: %d\n", p.T("label.suppressed_count"), report.SuppressedCount()))
+	}
+	sb.WriteString("\n")
+
+	// 与上一次扫描相比
+	if diff := report.Diff; diff != nil {
+		sb.WriteString(p.T("txt.section", p.T("section.diff")))
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", p.T("label.previous_scan"), p.ReportTime(diff.PreviousStartTime)))
+		sb.WriteString("  " + p.T("diff.summary",
+			len(diff.NewHits), len(diff.ResolvedHits), len(diff.ChangedHits), diff.PersistingCount, len(diff.NewFindings)) + "\n")
+		for _, change := range diff.NewHits {
+			sb.WriteString(fmt.Sprintf("  + %s  %s: %s\n", change.URL, change.Keyword, p.T("count.times", change.Current)))
+		}
+		for _, change := range diff.ChangedHits {
+			sb.WriteString(fmt.Sprintf("  ~ %s  %s: %d → %s\n", change.URL, change.Keyword, change.Previous, p.T("count.times", change.Current)))
+		}
+		for _, change := range diff.ResolvedHits {
+			sb.WriteString(fmt.Sprintf("  - %s  %s: %s\n", change.URL, change.Keyword, p.T("diff.previous_times", change.Previous)))
+		}
+		for _, finding := range diff.NewFindings {
+			sb.WriteString(fmt.Sprintf("  + [%s] %s - %s\n", finding.Severity, finding.URL, finding.Title))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 统计走势，变化达到阈值的指标以 ! 标出
+	if trend := report.Trend; trend != nil {
+		sb.WriteString(p.T("txt.section", p.T("section.trend")))
+		sb.WriteString("  " + trendSummary(p, trend) + "\n")
+		for _, series := range trend.Series {
+			marker := " "
+			if series.Significant {
+				marker = "!"
+			}
+			sb.WriteString(fmt.Sprintf(" %s %s  %s  %s: %s  %s: %s  %s\n", marker, p.TrendLabel(series), sparkline(series.Values),
+				p.T("label.current"), p.TrendValue(series.Metric, series.Current),
+				p.T("label.average", trend.AverageDays), trendAverage(p, series), trendPercent(series)))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 附加发现
+	if len(report.Findings) > 0 {
+		sb.WriteString(p.T("txt.section", p.T("section.findings")))
+		for i, finding := range report.Findings {
+			sb.WriteString(fmt.Sprintf("  [%d] [%s] %s - %s\n", i+1, finding.Severity, finding.URL, finding.Title))
+			if finding.Detail != "" {
+				sb.WriteString(fmt.Sprintf("      %s\n", finding.Detail))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// 扫描错误
+	if len(report.Errors) > 0 {
+		sb.WriteString(p.T("txt.section", p.T("section.errors")))
+		for i, scanErr := range report.Errors {
+			sb.WriteString(fmt.Sprintf("  [%d] %s (%s %d)\n", i+1, scanErr.URL, p.T("label.depth"), scanErr.Depth))
+			sb.WriteString(fmt.Sprintf("      %s\n", scanErr.Error))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 已豁免命中
+	if len(report.Suppressed) > 0 {
+		sb.WriteString(p.T("txt.section", p.T("section.suppressed")))
+		sb.WriteString("  " + p.T("count.total_times", report.SuppressedCount()) + "\n")
+		for _, s := range report.Suppressed {
+			sb.WriteString(fmt.Sprintf("  - %s  %s: %s", s.URL, s.Keyword, p.T("count.times", s.Count)))
+			if s.SnippetHash != "" {
+				sb.WriteString(fmt.Sprintf("  %s %s", p.T("label.snippet"), s.SnippetHash))
+			}
+			if s.Reason != "" {
+				sb.WriteString(fmt.Sprintf("  %s: %s", p.T("label.reason"), s.Reason))
+			}
+			if s.Expires != "" {
+				sb.WriteString(fmt.Sprintf("  %s: %s", p.T("label.expires"), s.Expires))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	// 匹配结果
+	if len(report.Results) > 0 {
+		sb.WriteString("-" + strings.Repeat("-", 79) + "\n")
+		sb.WriteString("                           " + p.T("section.results_detail") + "\n")
+		sb.WriteString("-" + strings.Repeat("-", 79) + "\n\n")
+
+		for i, result := range sortedResults(report) {
+			sb.WriteString(fmt.Sprintf("[%d] URL: %s\n", i+1, result.URL))
+			sb.WriteString(fmt.Sprintf("    %s: %d\n", p.T("label.page_depth"), result.Depth))
+			if result.Change != "" {
+				sb.WriteString(fmt.Sprintf("    %s: %s\n", p.T("label.change_since_previous"), changeLabel(p, result.Change)))
+			}
+			if result.WARCRecordID != "" {
+				sb.WriteString(fmt.Sprintf("    %s: %s\n", p.T("label.warc_record"), result.WARCRecordID))
+			}
+			if result.SHA256 != "" {
+				sb.WriteString(fmt.Sprintf("    SHA-256: %s\n", result.SHA256))
+			}
+			if result.SnapshotDir != "" {
+				sb.WriteString(fmt.Sprintf("    %s: %s\n", p.T("label.evidence"), result.SnapshotDir))
+			}
+			if result.Truncated {
+				sb.WriteString("    " + p.T("result.truncated", result.BodySize) + "\n")
+			}
+			sb.WriteString(fmt.Sprintf("    %s: %d\n", p.T("label.total_count"), result.TotalCount))
+			sb.WriteString(fmt.Sprintf("    %s: %s\n", p.T("label.found_keywords"), strings.Join(result.Keywords, ", ")))
+			sb.WriteString("    " + p.T("label.keyword_counts") + ":\n")
+			for keyword, count := range result.KeywordCounts {
+				sb.WriteString(fmt.Sprintf("      - %s: %s\n", keyword, p.T("count.times", count)))
+			}
+			if len(result.Hits) > 0 {
+				sb.WriteString("    " + p.T("txt.snippets") + ":\n")
+				for i, hit := range result.Hits {
+					if i == maxReportSnippets {
+						sb.WriteString("      … " + p.T("count.total_hits", len(result.Hits)) + "\n")
+						break
+					}
+					sb.WriteString(fmt.Sprintf("      - [%s] %s: %s\n", hit.SnippetHash, hit.Keyword, hit.Snippet))
+				}
+			}
+			if variants := hitVariants(p, result); len(variants) > 0 {
+				sb.WriteString(fmt.Sprintf("    %s: %s\n", p.T("label.variants"), strings.Join(variants, ", ")))
+			}
+			if len(result.Sensitive) > 0 {
+				sb.WriteString(fmt.Sprintf("    %s: %s\n", p.T("label.sensitive"), p.T("count.places", len(result.Sensitive))))
+				for _, hit := range result.Sensitive {
+					sb.WriteString(fmt.Sprintf("      - [%s] %s\n", hit.Detector, hit.Value))
+				}
+			}
+			if result.HiddenCount > 0 {
+				sb.WriteString(fmt.Sprintf("    %s: %s\n", p.T("label.hidden_hits"), p.T("count.times", result.HiddenCount)))
+				for _, hit := range result.Hits {
+					if hit.Hidden {
+						sb.WriteString(fmt.Sprintf("      - %s [%s] %s: %s\n", hit.Keyword, hit.HiddenReason, hit.Element, hit.Snippet))
+					}
+				}
+			}
+			sb.WriteString("\n")
+		}
+	} else {
+		sb.WriteString(p.T("txt.section", p.T("section.results")))
+		sb.WriteString("  " + p.T("result.none") + "\n\n")
+	}
+
+	// 报告尾部
+	sb.WriteString("=" + strings.Repeat("=", 79) + "\n")
+	sb.WriteString("                           " + p.T("report.end") + "\n")
+	sb.WriteString("=" + strings.Repeat("=", 79) + "\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
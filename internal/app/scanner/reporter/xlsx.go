@@ -0,0 +1,279 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+	"github.com/gw-gong/key-spy/internal/pkg/xlsx"
+)
+
+// reviewStatusKeys 审核状态列的下拉选项，由审核人员在 Excel 中填写
+var reviewStatusKeys = []string{"review.pending", "review.confirmed", "review.false_positive", "review.fixed", "review.accepted"}
+
+// xlsxFormatter 供合规审核使用的 Excel 报告
+type xlsxFormatter struct {
+	p *i18n.Printer
+}
+
+func (f *xlsxFormatter) Name() string {
+	return FormatXLSX
+}
+
+func (f *xlsxFormatter) Extension() string {
+	return "xlsx"
+}
+
+func (f *xlsxFormatter) Format(w io.Writer, report *model.ScanReport) error {
+	p := f.p
+	results := sortedResults(report)
+
+	wb := xlsx.NewWorkbook()
+	addSummarySheet(wb, p, report)
+	addPagesSheet(wb, p, results)
+	addHitsSheet(wb, p, results)
+	addErrorsSheet(wb, p, report)
+	if report.Diff != nil {
+		addDiffSheet(wb, p, report.Diff)
+	}
+	if report.Trend != nil {
+		addTrendSheet(wb, p, report.Trend)
+	}
+	addPivotSheet(wb, p, report, results)
+	addFindingsSheet(wb, p, report)
+
+	if err := wb.Write(w); err != nil {
+		return fmt.Errorf("failed to write xlsx report: %w", err)
+	}
+	return nil
+}
+
+// addSummarySheet 扫描信息与统计摘要
+func addSummarySheet(wb *xlsx.Workbook, p *i18n.Printer, report *model.ScanReport) {
+	sheet := wb.AddSheet(p.T("xlsx.sheet.summary"))
+	sheet.SetHeader(p.T("label.item"), p.T("label.value"))
+	sheet.SetColumns(xlsx.Column{Width: 20}, xlsx.Column{Width: 80})
+
+	scanMode := report.ScanMode
+	switch scanMode {
+	case model.ScanModeOnline:
+		scanMode = p.T("scan_mode.online")
+	case model.ScanModeLocal:
+		scanMode = p.T("scan_mode.local")
+	case model.ScanModeOffline:
+		scanMode = p.T("scan_mode.offline")
+	}
+	sheet.AddRow(p.T("label.target"), report.TargetURL)
+	sheet.AddRow(p.T("label.keywords"), strings.Join(report.Keywords, ", "))
+	sheet.AddRow(p.T("label.start_time"), p.ReportTime(report.StartTime))
+	sheet.AddRow(p.T("label.end_time"), p.ReportTime(report.EndTime))
+	sheet.AddRow(p.T("label.duration"), p.Duration(report.Duration))
+	sheet.AddRow(p.T("label.scan_mode"), scanMode)
+	if report.WARCFile != "" {
+		sheet.AddRow(p.T("label.warc"), report.WARCFile)
+	}
+	if report.EvidenceDir != "" {
+		sheet.AddRow(p.T("label.evidence"), report.EvidenceDir)
+	}
+	sheet.AddRow(p.T("label.total_pages"), report.TotalPages)
+	sheet.AddRow(p.T("label.match_pages"), report.MatchPages)
+	sheet.AddRow(p.T("label.error_count"), report.ErrorCount)
+	sheet.AddRow(p.T("label.finding_count"), len(report.Findings))
+	sheet.AddRow(p.T("label.suppressed_count"), report.SuppressedCount())
+	if diff := report.Diff; diff != nil {
+		sheet.AddRow(p.T("label.previous_scan"), p.ReportTime(diff.PreviousStartTime))
+		sheet.AddRow(p.T("label.new_hits"), len(diff.NewHits))
+		sheet.AddRow(p.T("label.resolved_hits"), len(diff.ResolvedHits))
+		sheet.AddRow(p.T("label.changed_hits"), len(diff.ChangedHits))
+		sheet.AddRow(p.T("label.new_findings"), len(diff.NewFindings))
+	}
+}
+
+// addPagesSheet 每行一个匹配页面
+func addPagesSheet(wb *xlsx.Workbook, p *i18n.Printer, results []*model.ScanResult) {
+	sheet := wb.AddSheet(p.T("xlsx.sheet.pages"))
+	sheet.SetHeader(p.T("label.index"), "URL", p.T("label.depth"), p.T("label.occurrences"), p.T("label.keyword"),
+		p.T("label.hidden_hits"), p.T("label.sensitive"), "SHA-256", p.T("label.change"), p.T("label.review_status"), p.T("label.review_note"))
+	sheet.SetColumns(
+		xlsx.Column{Width: 6}, xlsx.Column{Width: 60}, xlsx.Column{Width: 6}, xlsx.Column{Width: 10},
+		xlsx.Column{Width: 30}, xlsx.Column{Width: 10}, xlsx.Column{Width: 10}, xlsx.Column{Width: 20},
+		xlsx.Column{Width: 8}, xlsx.Column{Width: 12}, xlsx.Column{Width: 40, Wrap: true},
+	)
+	sheet.AddListValidation(9, reviewStatuses(p)...)
+
+	for i, result := range results {
+		sheet.AddRow(i+1, result.URL, result.Depth, result.TotalCount, strings.Join(result.Keywords, ", "),
+			result.HiddenCount, len(result.Sensitive), result.SHA256, changeLabel(p, result.Change))
+	}
+}
+
+// addHitsSheet 每行一次命中，附带上下文片段
+func addHitsSheet(wb *xlsx.Workbook, p *i18n.Printer, results []*model.ScanResult) {
+	sheet := wb.AddSheet(p.T("xlsx.sheet.hits"))
+	sheet.SetHeader("URL", p.T("label.keyword"), p.T("label.variant"), p.T("label.distance"), p.T("label.location"),
+		p.T("label.element"), p.T("label.offset"), p.T("label.hidden"), p.T("label.hidden_reason"),
+		p.T("label.snippet_hash"), p.T("label.snippet"), p.T("label.review_status"), p.T("label.review_note"))
+	sheet.SetColumns(
+		xlsx.Column{Width: 50}, xlsx.Column{Width: 14}, xlsx.Column{Width: 14}, xlsx.Column{Width: 8},
+		xlsx.Column{Width: 10}, xlsx.Column{Width: 24}, xlsx.Column{Width: 8}, xlsx.Column{Width: 6},
+		xlsx.Column{Width: 14}, xlsx.Column{Width: 18}, xlsx.Column{Width: 60, Wrap: true},
+		xlsx.Column{Width: 12}, xlsx.Column{Width: 40, Wrap: true},
+	)
+	sheet.AddListValidation(11, reviewStatuses(p)...)
+
+	for _, result := range results {
+		for _, hit := range result.Hits {
+			hidden := ""
+			if hit.Hidden {
+				hidden = p.T("label.yes")
+			}
+			sheet.AddRow(result.URL, hit.Keyword, hit.Variant, hit.Distance, hit.Location, hit.Element,
+				hit.Offset, hidden, hit.HiddenReason, hit.SnippetHash, hit.Snippet)
+		}
+	}
+}
+
+// addErrorsSheet 抓取或解析失败的页面
+func addErrorsSheet(wb *xlsx.Workbook, p *i18n.Printer, report *model.ScanReport) {
+	sheet := wb.AddSheet(p.T("section.errors"))
+	sheet.SetHeader("URL", p.T("label.depth"), p.T("label.error"))
+	sheet.SetColumns(xlsx.Column{Width: 60}, xlsx.Column{Width: 6}, xlsx.Column{Width: 80, Wrap: true})
+
+	for _, scanErr := range report.Errors {
+		sheet.AddRow(scanErr.URL, scanErr.Depth, scanErr.Error)
+	}
+}
+
+// addDiffSheet 与上一次扫描相比的变化
+func addDiffSheet(wb *xlsx.Workbook, p *i18n.Printer, diff *model.ScanDiff) {
+	sheet := wb.AddSheet(p.T("xlsx.sheet.diff"))
+	sheet.SetHeader(p.T("label.change"), "URL", p.T("label.keyword"), p.T("label.previous_count"), p.T("label.current_count"))
+	sheet.SetColumns(xlsx.Column{Width: 10}, xlsx.Column{Width: 60}, xlsx.Column{Width: 20}, xlsx.Column{Width: 10}, xlsx.Column{Width: 10})
+
+	for _, change := range diff.NewHits {
+		sheet.AddRow(p.T("change.new"), change.URL, change.Keyword, change.Previous, change.Current)
+	}
+	for _, change := range diff.ChangedHits {
+		sheet.AddRow(p.T("label.changed_hits"), change.URL, change.Keyword, change.Previous, change.Current)
+	}
+	for _, change := range diff.ResolvedHits {
+		sheet.AddRow(p.T("label.resolved_hits"), change.URL, change.Keyword, change.Previous, change.Current)
+	}
+}
+
+// addTrendSheet 各指标的统计走势，每次扫描一列，便于在 Excel 中绘制图表
+func addTrendSheet(wb *xlsx.Workbook, p *i18n.Printer, trend *model.ScanTrend) {
+	sheet := wb.AddSheet(p.T("xlsx.sheet.trend"))
+	header := []string{p.T("label.metric"), p.T("label.current"), p.T("label.average", trend.AverageDays), p.T("label.change")}
+	columns := []xlsx.Column{{Width: 24}, {Width: 12}, {Width: 14}, {Width: 10}}
+	for _, point := range trend.Points {
+		header = append(header, p.ReportTime(point.StartTime))
+		columns = append(columns, xlsx.Column{Width: 20})
+	}
+	sheet.SetHeader(header...)
+	sheet.SetColumns(columns...)
+
+	for _, series := range trend.Series {
+		label := p.TrendLabel(series)
+		if series.Metric == model.TrendMetricDuration {
+			label = p.T("trend.metric.seconds")
+		}
+		row := []interface{}{label, p.TrendValue(series.Metric, series.Current), trendAverage(p, series), trendPercent(series)}
+		for _, value := range series.Values {
+			row = append(row, math.Round(value*10)/10)
+		}
+		sheet.AddRow(row...)
+	}
+}
+
+// addPivotSheet 页面 × 关键词的出现次数透视表
+func addPivotSheet(wb *xlsx.Workbook, p *i18n.Printer, report *model.ScanReport, results []*model.ScanResult) {
+	keywords := pivotKeywords(report, results)
+
+	sheet := wb.AddSheet(p.T("xlsx.sheet.pivot"))
+	header := append([]string{"URL"}, keywords...)
+	sheet.SetHeader(append(header, p.T("label.total"))...)
+	columns := []xlsx.Column{{Width: 60}}
+	for range keywords {
+		columns = append(columns, xlsx.Column{Width: 12})
+	}
+	sheet.SetColumns(append(columns, xlsx.Column{Width: 10})...)
+
+	totals := make([]interface{}, len(keywords)+2)
+	totals[0] = p.T("label.total")
+	sum := 0
+	for _, result := range results {
+		row := make([]interface{}, len(keywords)+2)
+		row[0] = result.URL
+		for i, keyword := range keywords {
+			if count := result.KeywordCounts[keyword]; count > 0 {
+				row[i+1] = count
+				totals[i+1] = intValue(totals[i+1]) + count
+			}
+		}
+		row[len(keywords)+1] = result.TotalCount
+		sum += result.TotalCount
+		sheet.AddRow(row...)
+	}
+	totals[len(keywords)+1] = sum
+	sheet.AddRow(totals...)
+}
+
+// addFindingsSheet 附加发现
+func addFindingsSheet(wb *xlsx.Workbook, p *i18n.Printer, report *model.ScanReport) {
+	sheet := wb.AddSheet(p.T("section.findings"))
+	sheet.SetHeader(p.T("label.severity"), p.T("label.type"), "URL", p.T("label.title"), p.T("label.detail"),
+		p.T("label.review_status"), p.T("label.review_note"))
+	sheet.SetColumns(
+		xlsx.Column{Width: 10}, xlsx.Column{Width: 14}, xlsx.Column{Width: 50}, xlsx.Column{Width: 40},
+		xlsx.Column{Width: 60, Wrap: true}, xlsx.Column{Width: 12}, xlsx.Column{Width: 40, Wrap: true},
+	)
+	sheet.AddListValidation(5, reviewStatuses(p)...)
+
+	for _, finding := range report.Findings {
+		sheet.AddRow(finding.Severity, finding.Type, finding.URL, finding.Title, finding.Detail)
+	}
+}
+
+// reviewStatuses 审核状态的下拉选项
+func reviewStatuses(p *i18n.Printer) []string {
+	statuses := make([]string, 0, len(reviewStatusKeys))
+	for _, key := range reviewStatusKeys {
+		statuses = append(statuses, p.T(key))
+	}
+	return statuses
+}
+
+// pivotKeywords 透视表的关键词列：先按配置顺序，再补充结果中出现的其他关键词
+func pivotKeywords(report *model.ScanReport, results []*model.ScanResult) []string {
+	seen := make(map[string]bool)
+	keywords := make([]string, 0, len(report.Keywords))
+	for _, keyword := range report.Keywords {
+		if !seen[keyword] {
+			seen[keyword] = true
+			keywords = append(keywords, keyword)
+		}
+	}
+	extra := make([]string, 0)
+	for _, result := range results {
+		for keyword := range result.KeywordCounts {
+			if !seen[keyword] {
+				seen[keyword] = true
+				extra = append(extra, keyword)
+			}
+		}
+	}
+	sort.Strings(extra)
+	return append(keywords, extra...)
+}
+
+func intValue(v interface{}) int {
+	if n, ok := v.(int); ok {
+		return n
+	}
+	return 0
+}
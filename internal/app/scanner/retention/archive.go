@@ -0,0 +1,236 @@
+package retention
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// compressGroup 压缩扫描的报告与证据快照，WARC 归档由 output.warc.compress 控制，不在此处理；
+// 返回节省的字节数以及是否有文件被压缩
+func (r *retention) compressGroup(group *scanGroup, format string) (int64, bool, error) {
+	targets := make([]string, 0, len(group.paths))
+	kept := make([]string, 0, len(group.paths))
+	for _, path := range group.paths {
+		if isCompressed(path) {
+			kept = append(kept, path)
+		} else {
+			targets = append(targets, path)
+		}
+	}
+	if len(targets) == 0 {
+		return 0, false, nil
+	}
+
+	before, err := totalSize(targets)
+	if err != nil {
+		return 0, false, err
+	}
+
+	archives := make([]string, 0, len(targets))
+	switch format {
+	case "", FormatGzip:
+		for _, path := range targets {
+			info, err := os.Stat(path)
+			if err != nil {
+				return 0, false, err
+			}
+			var archive string
+			if info.IsDir() {
+				archive, err = tarGzDir(path)
+			} else {
+				archive, err = gzipFile(path)
+			}
+			if err != nil {
+				return 0, false, err
+			}
+			archives = append(archives, archive)
+		}
+	case FormatZip:
+		archive := filepath.Join(r.cfg.Output.Dir, fmt.Sprintf("%s_%s.zip", r.cfg.Output.FilePrefix, group.id))
+		if _, err := os.Stat(archive); err == nil {
+			return 0, false, fmt.Errorf("archive already exists: %s", archive)
+		}
+		if err := zipPaths(archive, targets); err != nil {
+			return 0, false, err
+		}
+		archives = append(archives, archive)
+	default:
+		return 0, false, fmt.Errorf("unknown compress format: %s", format)
+	}
+
+	// 归档写入成功后再删除原文件
+	for _, path := range targets {
+		if err := os.RemoveAll(path); err != nil {
+			return 0, true, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	group.paths = append(kept, archives...)
+
+	after, err := totalSize(archives)
+	if err != nil {
+		return 0, true, err
+	}
+	return before - after, true, nil
+}
+
+// isCompressed 已压缩的文件与 WARC 归档不再处理
+func isCompressed(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasSuffix(name, ".gz") || strings.HasSuffix(name, ".zip") || strings.HasSuffix(name, ".warc")
+}
+
+// gzipFile 将文件压缩为同名 .gz 文件
+func gzipFile(path string) (string, error) {
+	archive := path + ".gz"
+	err := writeArchive(archive, func(w io.Writer) error {
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		gz := gzip.NewWriter(w)
+		gz.Name = filepath.Base(path)
+		if _, err := io.Copy(gz, src); err != nil {
+			return err
+		}
+		return gz.Close()
+	})
+	return archive, err
+}
+
+// tarGzDir 将目录打包为同名 .tar.gz 文件
+func tarGzDir(dir string) (string, error) {
+	archive := dir + ".tar.gz"
+	err := writeArchive(archive, func(w io.Writer) error {
+		gz := gzip.NewWriter(w)
+		tw := tar.NewWriter(gz)
+		base := filepath.Dir(dir)
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(base, path)
+			if err != nil {
+				return err
+			}
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = filepath.ToSlash(rel)
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			return copyFile(tw, path)
+		})
+		if err != nil {
+			return err
+		}
+		if err := tw.Close(); err != nil {
+			return err
+		}
+		return gz.Close()
+	})
+	return archive, err
+}
+
+// zipPaths 将文件与目录打包为一个 zip 文件
+func zipPaths(archive string, paths []string) error {
+	return writeArchive(archive, func(w io.Writer) error {
+		zw := zip.NewWriter(w)
+		for _, root := range paths {
+			base := filepath.Dir(root)
+			err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.Type().IsRegular() {
+					return nil
+				}
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(base, path)
+				if err != nil {
+					return err
+				}
+				header, err := zip.FileInfoHeader(info)
+				if err != nil {
+					return err
+				}
+				header.Name = filepath.ToSlash(rel)
+				header.Method = zip.Deflate
+				fw, err := zw.CreateHeader(header)
+				if err != nil {
+					return err
+				}
+				return copyFile(fw, path)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return zw.Close()
+	})
+}
+
+// writeArchive 先写临时文件再重命名，失败时删除临时文件
+func writeArchive(archive string, write func(w io.Writer) error) error {
+	tmpPath := archive + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write archive %s: %w", archive, err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close archive %s: %w", archive, err)
+	}
+	if err := os.Rename(tmpPath, archive); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save archive %s: %w", archive, err)
+	}
+	return nil
+}
+
+func copyFile(w io.Writer, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	_, err = io.Copy(w, src)
+	return err
+}
+
+func totalSize(paths []string) (int64, error) {
+	var total int64
+	for _, path := range paths {
+		size, err := dirSize(path)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
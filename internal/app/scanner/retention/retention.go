@@ -0,0 +1,21 @@
+package retention
+
+import (
+	"context"
+)
+
+// Retention 输出目录清理接口
+type Retention interface {
+	// Apply 按保留策略删除过期扫描、压缩较早的报告与证据快照，并执行磁盘配额
+	Apply(ctx context.Context) (*Summary, error)
+}
+
+// Summary 一次清理的结果
+type Summary struct {
+	Scans           int   // 清理前的扫描数
+	DeletedScans    int   // 按保留策略删除的扫描数
+	EvictedScans    int   // 因超出磁盘配额删除的扫描数
+	CompressedScans int   // 本次压缩归档的扫描数
+	FreedBytes      int64 // 删除与压缩释放的字节数
+	TotalBytes      int64 // 清理后输出目录的总大小
+}
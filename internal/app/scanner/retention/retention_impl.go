@@ -0,0 +1,330 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/app/scanner/history"
+	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+	"github.com/gw-gong/key-spy/internal/pkg/warc"
+
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+// scanIDLayout 报告、WARC 归档与证据快照文件名中的扫描时间格式
+const scanIDLayout = "20060102_150405"
+
+// 压缩格式
+const (
+	FormatGzip = "gzip" // 逐个文件压缩，证据快照目录打包为 tar.gz
+	FormatZip  = "zip"  // 每次扫描的报告与证据快照打包为一个 zip
+)
+
+type retention struct {
+	cfg     *localcfg.Config
+	history history.Store
+}
+
+// scanGroup 同一次扫描在输出目录中的文件与扫描历史记录
+type scanGroup struct {
+	id         string
+	time       time.Time
+	paths      []string // 输出目录下属于该扫描的文件或目录
+	historyIDs []string
+}
+
+// NewRetention 创建输出目录清理器
+func NewRetention(cfg *localcfg.Config, history history.Store) Retention {
+	return &retention{
+		cfg:     cfg,
+		history: history,
+	}
+}
+
+func (r *retention) Apply(ctx context.Context) (*Summary, error) {
+	cfg := r.cfg.Output.Retention
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+
+	groups, err := r.scanGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary := &Summary{Scans: len(groups)}
+	now := time.Now()
+
+	// 按保留策略删除，未配置任何规则时全部保留
+	if cfg.KeepLast > 0 || cfg.KeepDaily > 0 || cfg.KeepWeekly > 0 {
+		keep := keepSet(groups, cfg, now)
+		remaining := make([]*scanGroup, 0, len(groups))
+		for _, group := range groups {
+			if keep[group.id] {
+				remaining = append(remaining, group)
+				continue
+			}
+			outputFreed, historyFreed := r.deleteGroup(ctx, group)
+			summary.FreedBytes += outputFreed + historyFreed
+			summary.DeletedScans++
+		}
+		groups = remaining
+	}
+
+	// 压缩较早的扫描，最新一次扫描始终保持原样
+	if cfg.CompressAfterDays > 0 {
+		cutoff := now.AddDate(0, 0, -cfg.CompressAfterDays)
+		for i, group := range groups {
+			if i == 0 || !group.time.Before(cutoff) {
+				continue
+			}
+			saved, compressed, err := r.compressGroup(group, cfg.CompressFormat)
+			if err != nil {
+				log.Warnc(ctx, "Failed to compress scan output", log.Str("scan_id", group.id), log.Err(err))
+				continue
+			}
+			if compressed {
+				summary.CompressedScans++
+				summary.FreedBytes += saved
+			}
+		}
+	}
+
+	total, err := dirSize(r.cfg.Output.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// 磁盘配额：从最早的扫描开始删除，最新一次扫描不会被删除；总大小只统计一次，删除后减去释放的字节数
+	if cfg.MaxSizeMB > 0 {
+		limit := int64(cfg.MaxSizeMB) << 20
+		historyInOutput := r.historyInOutput()
+		for i := len(groups) - 1; i > 0 && total > limit; i-- {
+			outputFreed, historyFreed := r.deleteGroup(ctx, groups[i])
+			summary.FreedBytes += outputFreed + historyFreed
+			summary.EvictedScans++
+			total -= outputFreed
+			if historyInOutput {
+				total -= historyFreed
+			}
+		}
+		if total > limit {
+			log.Warnc(ctx, "Output directory still exceeds quota",
+				log.Int64("total_bytes", total),
+				log.Int("max_size_mb", cfg.MaxSizeMB),
+			)
+		}
+	}
+	summary.TotalBytes = total
+
+	log.Infoc(ctx, "Output retention applied",
+		log.Int("scans", summary.Scans),
+		log.Int("deleted_scans", summary.DeletedScans),
+		log.Int("evicted_scans", summary.EvictedScans),
+		log.Int("compressed_scans", summary.CompressedScans),
+		log.Int64("freed_bytes", summary.FreedBytes),
+		log.Int64("total_bytes", summary.TotalBytes),
+	)
+	return summary, nil
+}
+
+// scanGroups 按扫描时间汇总输出目录中的文件与扫描历史，按时间倒序排列
+func (r *retention) scanGroups(ctx context.Context) ([]*scanGroup, error) {
+	groups := make(map[string]*scanGroup)
+	group := func(id string) *scanGroup {
+		if g, ok := groups[id]; ok {
+			return g
+		}
+		t, err := time.ParseInLocation(scanIDLayout, id, time.Local)
+		if err != nil {
+			return nil
+		}
+		g := &scanGroup{id: id, time: t}
+		groups[id] = g
+		return g
+	}
+
+	entries, err := os.ReadDir(r.cfg.Output.Dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read output directory: %w", err)
+	}
+	nameRe := regexp.MustCompile(`^` + regexp.QuoteMeta(r.cfg.Output.FilePrefix) + `_(\d{8}_\d{6})(?:[._]|$)`)
+	offlineSource := r.offlineSource()
+	for _, entry := range entries {
+		m := nameRe.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		// 离线重扫读取的 WARC 归档不随所属扫描删除
+		if offlineSource != "" && sameFile(offlineSource, filepath.Join(r.cfg.Output.Dir, entry.Name())) {
+			continue
+		}
+		if g := group(m[1]); g != nil {
+			g.paths = append(g.paths, filepath.Join(r.cfg.Output.Dir, entry.Name()))
+		}
+	}
+
+	records, err := r.history.ListScans(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scan history: %w", err)
+	}
+	for _, record := range records {
+		if g := group(record.StartTime.In(time.Local).Format(scanIDLayout)); g != nil {
+			g.historyIDs = append(g.historyIDs, record.ID)
+		}
+	}
+
+	sorted := make([]*scanGroup, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].time.After(sorted[j].time)
+	})
+	return sorted, nil
+}
+
+// keepSet 返回按保留策略需要保留的扫描，groups 按时间倒序排列
+func keepSet(groups []*scanGroup, cfg *localcfg.RetentionConfig, now time.Time) map[string]bool {
+	keep := make(map[string]bool)
+	if len(groups) > 0 {
+		keep[groups[0].id] = true
+	}
+
+	today := startOfDay(now)
+	firstDay := today.AddDate(0, 0, -(cfg.KeepDaily - 1))
+	firstWeek := startOfWeek(today).AddDate(0, 0, -7*(cfg.KeepWeekly-1))
+	days := make(map[time.Time]bool)
+	weeks := make(map[time.Time]bool)
+	for i, group := range groups {
+		if i < cfg.KeepLast {
+			keep[group.id] = true
+		}
+
+		// 每天、每周保留最后一次扫描
+		day := startOfDay(group.time)
+		if cfg.KeepDaily > 0 && !day.Before(firstDay) && !days[day] {
+			days[day] = true
+			keep[group.id] = true
+		}
+		week := startOfWeek(day)
+		if cfg.KeepWeekly > 0 && !week.Before(firstWeek) && !weeks[week] {
+			weeks[week] = true
+			keep[group.id] = true
+		}
+	}
+	return keep
+}
+
+// offlineSource 返回配置的离线重扫来源 WARC 文件，未启用或无法确定时返回空字符串
+func (r *retention) offlineSource() string {
+	if r.cfg.Scanner == nil || r.cfg.Scanner.Offline == nil || !r.cfg.Scanner.Offline.Enabled {
+		return ""
+	}
+	source, err := warc.ResolveSource(r.cfg.Scanner.Offline.Source)
+	if err != nil {
+		return ""
+	}
+	return source
+}
+
+// historyInOutput 扫描历史目录是否位于输出目录内，即是否计入输出目录的大小
+func (r *retention) historyInOutput() bool {
+	history := r.cfg.Output.History
+	if history == nil || history.Dir == "" {
+		return true
+	}
+	return isWithin(r.cfg.Output.Dir, history.Dir)
+}
+
+// deleteGroup 删除扫描的全部输出与历史记录，分别返回输出目录与扫描历史释放的字节数
+func (r *retention) deleteGroup(ctx context.Context, group *scanGroup) (int64, int64) {
+	var freed int64
+	for _, path := range group.paths {
+		size, err := dirSize(path)
+		if err != nil {
+			log.Warnc(ctx, "Failed to stat scan output", log.Str("path", path), log.Err(err))
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			log.Warnc(ctx, "Failed to remove scan output", log.Str("path", path), log.Err(err))
+			continue
+		}
+		freed += size
+	}
+
+	historyFreed, err := r.history.Delete(ctx, group.historyIDs)
+	if err != nil {
+		log.Warnc(ctx, "Failed to remove scan history", log.Str("scan_id", group.id), log.Err(err))
+	}
+	return freed, historyFreed
+}
+
+// sameFile 判断两个路径是否指向同一文件
+func sameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(infoA, infoB)
+}
+
+// isWithin 判断 path 是否位于 dir 内（含 dir 本身）
+func isWithin(dir, path string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek 返回所在周的周一
+func startOfWeek(day time.Time) time.Time {
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// dirSize 统计文件或目录的总大小，路径不存在时返回 0
+func dirSize(root string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute size of %s: %w", root, err)
+	}
+	return size, nil
+}
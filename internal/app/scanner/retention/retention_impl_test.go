@@ -0,0 +1,248 @@
+package retention
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/app/scanner/history"
+	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+const testPrefix = "scan_result"
+
+func TestKeepSet(t *testing.T) {
+	// 2026-03-18 为周三
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.Local)
+	groups := []*scanGroup{
+		{id: "a", time: time.Date(2026, 3, 18, 10, 0, 0, 0, time.Local)},
+		{id: "b", time: time.Date(2026, 3, 18, 8, 0, 0, 0, time.Local)},
+		{id: "c", time: time.Date(2026, 3, 17, 20, 0, 0, 0, time.Local)},
+		{id: "d", time: time.Date(2026, 3, 16, 9, 0, 0, 0, time.Local)},
+		{id: "e", time: time.Date(2026, 3, 15, 9, 0, 0, 0, time.Local)},
+		{id: "f", time: time.Date(2026, 3, 9, 9, 0, 0, 0, time.Local)},
+		{id: "g", time: time.Date(2026, 3, 8, 9, 0, 0, 0, time.Local)},
+		{id: "h", time: time.Date(2026, 2, 1, 9, 0, 0, 0, time.Local)},
+	}
+
+	tests := []struct {
+		name   string
+		cfg    *localcfg.RetentionConfig
+		groups []*scanGroup
+		want   []string
+	}{
+		{name: "keep last", cfg: &localcfg.RetentionConfig{KeepLast: 3}, groups: groups, want: []string{"a", "b", "c"}},
+		{name: "last scan of each day", cfg: &localcfg.RetentionConfig{KeepDaily: 2}, groups: groups, want: []string{"a", "c"}},
+		{name: "last scan of each week", cfg: &localcfg.RetentionConfig{KeepWeekly: 2}, groups: groups, want: []string{"a", "e"}},
+		{name: "daily and weekly", cfg: &localcfg.RetentionConfig{KeepDaily: 1, KeepWeekly: 3}, groups: groups, want: []string{"a", "e", "g"}},
+		{name: "latest scan is always kept", cfg: &localcfg.RetentionConfig{}, groups: groups, want: []string{"a"}},
+		{name: "no scans", cfg: &localcfg.RetentionConfig{KeepLast: 3}, groups: nil, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep := keepSet(tt.groups, tt.cfg, now)
+			got := make([]string, 0, len(keep))
+			for id := range keep {
+				got = append(got, id)
+			}
+			sort.Strings(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("keepSet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyKeepLast(t *testing.T) {
+	cfg := newTestConfig(t, &localcfg.RetentionConfig{Enabled: true, KeepLast: 2})
+	store := history.NewStore(cfg)
+	ids := []string{"20260101_100000", "20260102_100000", "20260103_100000"}
+	for _, id := range ids {
+		writeScan(t, cfg.Output.Dir, id, 100)
+		saveHistory(t, store, id)
+	}
+	writeFile(t, filepath.Join(cfg.Output.Dir, "notes.txt"), 10)
+
+	summary, err := NewRetention(cfg, store).Apply(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Scans != 3 || summary.DeletedScans != 1 || summary.EvictedScans != 0 {
+		t.Errorf("summary = %+v, want 3 scans and 1 deleted", *summary)
+	}
+
+	assertScanFiles(t, cfg.Output.Dir, ids[0], false)
+	assertScanFiles(t, cfg.Output.Dir, ids[1], true)
+	assertScanFiles(t, cfg.Output.Dir, ids[2], true)
+	assertExists(t, filepath.Join(cfg.Output.Dir, "notes.txt"), true)
+
+	records, err := store.ListScans(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Errorf("history has %d scans, want 2", len(records))
+	}
+}
+
+func TestApplyQuota(t *testing.T) {
+	cfg := newTestConfig(t, &localcfg.RetentionConfig{Enabled: true, MaxSizeMB: 1})
+	store := history.NewStore(cfg)
+	ids := []string{"20260101_100000", "20260102_100000", "20260103_100000"}
+	for _, id := range ids {
+		writeScan(t, cfg.Output.Dir, id, 400<<10)
+	}
+
+	summary, err := NewRetention(cfg, store).Apply(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 每次扫描约 1.2MB，只删除最早的扫描直到低于配额，最新一次扫描即使超出配额也保留
+	if summary.EvictedScans != 2 {
+		t.Errorf("evicted %d scans, want 2", summary.EvictedScans)
+	}
+	assertScanFiles(t, cfg.Output.Dir, ids[0], false)
+	assertScanFiles(t, cfg.Output.Dir, ids[1], false)
+	assertScanFiles(t, cfg.Output.Dir, ids[2], true)
+
+	total, err := dirSize(cfg.Output.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalBytes != total {
+		t.Errorf("summary total = %d bytes, want %d", summary.TotalBytes, total)
+	}
+}
+
+func TestApplyQuotaStopsBelowLimit(t *testing.T) {
+	cfg := newTestConfig(t, &localcfg.RetentionConfig{Enabled: true, MaxSizeMB: 1})
+	store := history.NewStore(cfg)
+	ids := []string{"20260101_100000", "20260102_100000", "20260103_100000"}
+	for _, id := range ids {
+		writeScan(t, cfg.Output.Dir, id, 100<<10)
+	}
+
+	summary, err := NewRetention(cfg, store).Apply(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 三次扫描约 0.9MB，未超出配额
+	if summary.EvictedScans != 0 {
+		t.Errorf("evicted %d scans, want 0", summary.EvictedScans)
+	}
+	for _, id := range ids {
+		assertScanFiles(t, cfg.Output.Dir, id, true)
+	}
+}
+
+func TestApplyKeepsOfflineSource(t *testing.T) {
+	cfg := newTestConfig(t, &localcfg.RetentionConfig{Enabled: true, KeepLast: 1, MaxSizeMB: 1})
+	ids := []string{"20260101_100000", "20260102_100000"}
+	for _, id := range ids {
+		writeScan(t, cfg.Output.Dir, id, 600<<10)
+	}
+	source := filepath.Join(cfg.Output.Dir, testPrefix+"_"+ids[0]+".warc.gz")
+	cfg.Scanner = &localcfg.ScannerConfig{Offline: &localcfg.OfflineConfig{Enabled: true, Source: source}}
+
+	summary, err := NewRetention(cfg, history.NewStore(cfg)).Apply(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.DeletedScans != 1 {
+		t.Errorf("deleted %d scans, want 1", summary.DeletedScans)
+	}
+
+	assertExists(t, source, true)
+	assertExists(t, filepath.Join(cfg.Output.Dir, testPrefix+"_"+ids[0]+".txt"), false)
+	assertExists(t, filepath.Join(cfg.Output.Dir, testPrefix+"_"+ids[0]+"_evidence"), false)
+	assertScanFiles(t, cfg.Output.Dir, ids[1], true)
+}
+
+func TestApplyDisabled(t *testing.T) {
+	cfg := newTestConfig(t, &localcfg.RetentionConfig{Enabled: false, KeepLast: 1})
+	ids := []string{"20260101_100000", "20260102_100000"}
+	for _, id := range ids {
+		writeScan(t, cfg.Output.Dir, id, 100)
+	}
+
+	summary, err := NewRetention(cfg, history.NewStore(cfg)).Apply(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != nil {
+		t.Errorf("summary = %+v, want nil", *summary)
+	}
+	for _, id := range ids {
+		assertScanFiles(t, cfg.Output.Dir, id, true)
+	}
+}
+
+func newTestConfig(t *testing.T, retention *localcfg.RetentionConfig) *localcfg.Config {
+	return &localcfg.Config{Output: &localcfg.OutputConfig{
+		Dir:        t.TempDir(),
+		FilePrefix: testPrefix,
+		History:    &localcfg.HistoryConfig{Enabled: true},
+		Retention:  retention,
+	}}
+}
+
+// writeScan 写入一次扫描的报告、WARC 归档与证据快照，每个文件 size 字节
+func writeScan(t *testing.T, dir, id string, size int) {
+	t.Helper()
+	base := filepath.Join(dir, testPrefix+"_"+id)
+	writeFile(t, base+".txt", size)
+	writeFile(t, base+".warc.gz", size)
+	writeFile(t, filepath.Join(base+"_evidence", "0001_page", "raw.html"), size)
+}
+
+// assertScanFiles 检查 writeScan 写入的文件是否全部存在或全部已删除
+func assertScanFiles(t *testing.T, dir, id string, exists bool) {
+	t.Helper()
+	base := filepath.Join(dir, testPrefix+"_"+id)
+	for _, path := range []string{base + ".txt", base + ".warc.gz", base + "_evidence"} {
+		assertExists(t, path, exists)
+	}
+}
+
+func assertExists(t *testing.T, path string, exists bool) {
+	t.Helper()
+	_, err := os.Stat(path)
+	if got := err == nil; got != exists {
+		t.Errorf("%s exists = %v, want %v", filepath.Base(path), got, exists)
+	}
+}
+
+func writeFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func saveHistory(t *testing.T, store history.Store, id string) {
+	t.Helper()
+	start, err := time.ParseInLocation(scanIDLayout, id, time.Local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := &model.ScanReport{
+		TargetURL: "https://example.com",
+		StartTime: start.Format("2006-01-02 15:04:05"),
+		EndTime:   start.Add(time.Minute).Format("2006-01-02 15:04:05"),
+	}
+	if _, err := store.Save(context.Background(), report); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -7,8 +7,12 @@ import (
 	"syscall"
 
 	"github.com/gw-gong/key-spy/internal/app/scanner/crawler"
+	"github.com/gw-gong/key-spy/internal/app/scanner/differ"
+	"github.com/gw-gong/key-spy/internal/app/scanner/history"
 	"github.com/gw-gong/key-spy/internal/app/scanner/notifier"
 	"github.com/gw-gong/key-spy/internal/app/scanner/reporter"
+	"github.com/gw-gong/key-spy/internal/app/scanner/retention"
+	"github.com/gw-gong/key-spy/internal/app/scanner/trend"
 	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
 
 	"github.com/gw-gong/gwkit-go/log"
@@ -17,10 +21,14 @@ import (
 )
 
 type scannerService struct {
-	cfg      *localcfg.Config
-	crawler  crawler.Crawler
-	reporter reporter.Reporter
-	notifier notifier.Notifier
+	cfg       *localcfg.Config
+	crawler   crawler.Crawler
+	reporter  reporter.Reporter
+	differ    differ.Differ
+	trend     trend.Trend
+	history   history.Store
+	notifier  notifier.Notifier
+	retention retention.Retention
 }
 
 // NewScannerService 创建扫描服务
@@ -28,13 +36,21 @@ func NewScannerService(
 	cfg *localcfg.Config,
 	crawler crawler.Crawler,
 	reporter reporter.Reporter,
+	differ differ.Differ,
+	trend trend.Trend,
+	history history.Store,
 	notifier notifier.Notifier,
+	retention retention.Retention,
 ) ScannerService {
 	return &scannerService{
-		cfg:      cfg,
-		crawler:  crawler,
-		reporter: reporter,
-		notifier: notifier,
+		cfg:       cfg,
+		crawler:   crawler,
+		reporter:  reporter,
+		differ:    differ,
+		trend:     trend,
+		history:   history,
+		notifier:  notifier,
+		retention: retention,
 	}
 }
 
@@ -82,21 +98,41 @@ func (s *scannerService) doScan(ctx context.Context) {
 		return
 	}
 
+	// 与上一次扫描比较，失败时仍生成报告
+	if err := s.differ.Apply(ctx, report); err != nil {
+		log.Errorc(ctx, "Failed to compare with previous scan", log.Err(err))
+	}
+
+	// 统计同一目标最近若干次扫描的走势，失败时仍生成报告
+	if err := s.trend.Apply(ctx, report); err != nil {
+		log.Errorc(ctx, "Failed to compute scan trend", log.Err(err))
+	}
+
+	// 记录扫描历史，需在比较与走势统计之后保存，避免计入本次扫描自身
+	if _, err := s.history.Save(ctx, report); err != nil {
+		log.Errorc(ctx, "Failed to save scan history", log.Err(err))
+	}
+
 	// 生成报告
-	filePath, err := s.reporter.GenerateReport(ctx, report)
+	filePaths, err := s.reporter.GenerateReport(ctx, report)
 	if err != nil {
 		log.Errorc(ctx, "Failed to generate report", log.Err(err))
 		return
 	}
 
 	log.Infoc(ctx, "Scan completed successfully",
-		log.Str("report_file", filePath),
+		log.Any("report_files", filePaths),
 		log.Int("total_pages", report.TotalPages),
 		log.Int("match_pages", report.MatchPages),
 	)
 
 	// 发送通知
-	if err := s.notifier.Notify(ctx, report, filePath); err != nil {
+	if err := s.notifier.Notify(ctx, report, filePaths); err != nil {
 		log.Errorc(ctx, "Failed to send notification", log.Err(err))
 	}
+
+	// 清理输出目录
+	if _, err := s.retention.Apply(ctx); err != nil {
+		log.Errorc(ctx, "Failed to apply output retention", log.Err(err))
+	}
 }
@@ -0,0 +1,13 @@
+package trend
+
+import (
+	"context"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// Trend 扫描统计走势接口
+type Trend interface {
+	// Apply 根据扫描历史中同一目标最近若干次扫描计算统计走势，写入 report.Trend
+	Apply(ctx context.Context, report *model.ScanReport) error
+}
@@ -0,0 +1,247 @@
+package trend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/app/scanner/history"
+	"github.com/gw-gong/key-spy/internal/config/scanner/localcfg"
+	"github.com/gw-gong/key-spy/internal/pkg/i18n"
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+
+	"github.com/gw-gong/gwkit-go/log"
+)
+
+const (
+	defaultDays             = 30
+	defaultMaxPoints        = 30
+	defaultAverageDays      = 7
+	defaultThresholdPercent = 20
+)
+
+// baselineModes 计入走势的扫描模式，离线重扫使用的是历史归档，不计入
+var baselineModes = []string{model.ScanModeOnline, model.ScanModeLocal}
+
+type trend struct {
+	cfg     *localcfg.Config
+	history history.Store
+}
+
+// NewTrend 创建扫描统计走势计算器
+func NewTrend(cfg *localcfg.Config, history history.Store) Trend {
+	return &trend{
+		cfg:     cfg,
+		history: history,
+	}
+}
+
+func (t *trend) Apply(ctx context.Context, report *model.ScanReport) error {
+	cfg := t.cfg.Output.Trend
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	if t.cfg.Output.History == nil || !t.cfg.Output.History.Enabled {
+		return errors.New("output.trend requires output.history to be enabled")
+	}
+
+	days := cfg.Days
+	if days <= 0 {
+		days = defaultDays
+	}
+	maxPoints := cfg.MaxPoints
+	if maxPoints <= 0 {
+		maxPoints = defaultMaxPoints
+	}
+	averageDays := cfg.AverageDays
+	if averageDays <= 0 {
+		averageDays = defaultAverageDays
+	}
+	threshold := cfg.ThresholdPercent
+	if threshold <= 0 {
+		threshold = defaultThresholdPercent
+	}
+
+	startTime, err := time.ParseInLocation(i18n.ReportTimeLayout, report.StartTime, time.Local)
+	if err != nil {
+		startTime = time.Now()
+	}
+	// 平均值周期可能比走势的统计天数更长，按两者中较长的一个查询
+	records, err := t.history.ListScans(ctx, &history.Query{
+		TargetURL: report.TargetURL,
+		Since:     startTime.AddDate(0, 0, -max(days, averageDays)),
+		Until:     startTime,
+		Modes:     baselineModes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list previous scans: %w", err)
+	}
+
+	// 平均值按平均值周期内的全部扫描计算，走势只展示统计天数内最近的若干次
+	averageSince := startTime.AddDate(0, 0, -averageDays)
+	shownSince := startTime.AddDate(0, 0, -days)
+	averaged := make([]*model.TrendPoint, 0)
+	shown := make([]*model.TrendPoint, 0, maxPoints)
+	for i, record := range records {
+		inAverage := !record.StartTime.Before(averageSince)
+		inShown := i < maxPoints-1 && !record.StartTime.Before(shownSince)
+		if !inAverage && !inShown {
+			break
+		}
+		point, err := t.recordPoint(ctx, record)
+		if err != nil {
+			log.Warnc(ctx, "Failed to load scan for trend", log.Str("scan_id", record.ID), log.Err(err))
+			continue
+		}
+		if inAverage {
+			averaged = append(averaged, point)
+		}
+		if inShown {
+			shown = append(shown, point)
+		}
+	}
+
+	// ListScans 按时间倒序返回，走势按时间升序展示
+	points := make([]*model.TrendPoint, 0, len(shown)+1)
+	for i := len(shown) - 1; i >= 0; i-- {
+		points = append(points, shown[i])
+	}
+	points = append(points, reportPoint(report))
+
+	report.Trend = &model.ScanTrend{
+		Days:        days,
+		AverageDays: averageDays,
+		Points:      points,
+		Series:      buildSeries(report.Keywords, points, averaged, threshold),
+	}
+
+	significant := 0
+	for _, series := range report.Trend.Series {
+		if series.Significant {
+			significant++
+		}
+	}
+	log.Infoc(ctx, "Computed scan trend",
+		log.Int("scans", len(points)),
+		log.Int("averaged_scans", len(averaged)),
+		log.Int("significant_changes", significant),
+	)
+	return nil
+}
+
+// recordPoint 将索引记录转换为走势中的一次扫描，早期记录没有各关键词次数时读取完整结果
+func (t *trend) recordPoint(ctx context.Context, record *history.Record) (*model.TrendPoint, error) {
+	keywordCounts := record.KeywordCounts
+	if keywordCounts == nil && record.HitCount > 0 {
+		scan, err := t.history.GetScan(ctx, record.ID)
+		if err != nil {
+			return nil, err
+		}
+		keywordCounts = countKeywords(scan)
+	}
+	duration, _ := time.ParseDuration(record.Duration)
+	return &model.TrendPoint{
+		StartTime:       record.StartTime.Format(i18n.ReportTimeLayout),
+		TotalPages:      record.TotalPages,
+		MatchPages:      record.MatchPages,
+		HitCount:        record.HitCount,
+		ErrorCount:      record.ErrorCount,
+		ErrorRate:       errorRate(record.ErrorCount, record.TotalPages),
+		DurationSeconds: duration.Seconds(),
+		KeywordCounts:   keywordCounts,
+	}, nil
+}
+
+// reportPoint 将本次扫描转换为走势中的一次扫描
+func reportPoint(report *model.ScanReport) *model.TrendPoint {
+	duration, _ := time.ParseDuration(report.Duration)
+	point := &model.TrendPoint{
+		StartTime:       report.StartTime,
+		TotalPages:      report.TotalPages,
+		MatchPages:      report.MatchPages,
+		ErrorCount:      report.ErrorCount,
+		ErrorRate:       errorRate(report.ErrorCount, report.TotalPages),
+		DurationSeconds: duration.Seconds(),
+		KeywordCounts:   countKeywords(report),
+	}
+	for _, result := range report.Results {
+		point.HitCount += result.TotalCount
+	}
+	return point
+}
+
+// countKeywords 汇总各关键词在所有页面中的出现次数
+func countKeywords(report *model.ScanReport) map[string]int {
+	counts := make(map[string]int)
+	for _, result := range report.Results {
+		for keyword, count := range result.KeywordCounts {
+			counts[keyword] += count
+		}
+	}
+	return counts
+}
+
+// errorRate 错误页面占比（百分比），页面总数包含抓取失败的页面
+func errorRate(errorCount, totalPages int) float64 {
+	if totalPages == 0 {
+		return 0
+	}
+	return float64(errorCount) * 100 / float64(totalPages)
+}
+
+// metric 走势中的一个指标
+type metric struct {
+	name    string
+	keyword string
+	value   func(point *model.TrendPoint) float64
+}
+
+// buildSeries 计算各指标的走势以及本次扫描相对平均值的变化
+func buildSeries(keywords []string, points, averaged []*model.TrendPoint, threshold float64) []*model.TrendSeries {
+	metrics := []*metric{
+		{name: model.TrendMetricTotalPages, value: func(p *model.TrendPoint) float64 { return float64(p.TotalPages) }},
+		{name: model.TrendMetricMatchPages, value: func(p *model.TrendPoint) float64 { return float64(p.MatchPages) }},
+		{name: model.TrendMetricHitCount, value: func(p *model.TrendPoint) float64 { return float64(p.HitCount) }},
+		{name: model.TrendMetricErrorRate, value: func(p *model.TrendPoint) float64 { return p.ErrorRate }},
+		{name: model.TrendMetricDuration, value: func(p *model.TrendPoint) float64 { return p.DurationSeconds }},
+	}
+	for _, keyword := range keywords {
+		keyword := keyword
+		metrics = append(metrics, &metric{
+			name:    model.TrendMetricKeyword,
+			keyword: keyword,
+			value:   func(p *model.TrendPoint) float64 { return float64(p.KeywordCounts[keyword]) },
+		})
+	}
+
+	current := points[len(points)-1]
+	seriesList := make([]*model.TrendSeries, 0, len(metrics))
+	for _, m := range metrics {
+		series := &model.TrendSeries{
+			Metric:     m.name,
+			Keyword:    m.keyword,
+			Values:     make([]float64, 0, len(points)),
+			Current:    m.value(current),
+			HasAverage: len(averaged) > 0,
+		}
+		for _, point := range points {
+			series.Values = append(series.Values, m.value(point))
+		}
+		if series.HasAverage {
+			sum := 0.0
+			for _, point := range averaged {
+				sum += m.value(point)
+			}
+			series.Average = sum / float64(len(averaged))
+			// 平均值为 0 时无法计算变化比例，新出现的命中由扫描对比报告
+			if series.Average > 0 {
+				series.Percent = (series.Current - series.Average) * 100 / series.Average
+				series.Significant = math.Abs(series.Percent) >= threshold
+			}
+		}
+		seriesList = append(seriesList, series)
+	}
+	return seriesList
+}
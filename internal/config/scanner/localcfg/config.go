@@ -1,6 +1,8 @@
 package localcfg
 
 import (
+	"errors"
+
 	"github.com/gw-gong/gwkit-go/hotcfg"
 	"github.com/gw-gong/gwkit-go/log"
 	"github.com/gw-gong/gwkit-go/setting"
@@ -9,12 +11,12 @@ import (
 
 type Config struct {
 	hotcfg.BaseConfigCapable
-	Env      setting.Env           `yaml:"env" mapstructure:"env"`
-	Scanner  *ScannerConfig        `yaml:"scanner" mapstructure:"scanner"`
-	Cron     *CronConfig           `yaml:"cron" mapstructure:"cron"`
-	Output   *OutputConfig         `yaml:"output" mapstructure:"output"`
-	Notifier *NotifierConfig       `yaml:"notifier" mapstructure:"notifier"`
-	Logger   *log.LoggerConfig     `yaml:"logger" mapstructure:"logger"`
+	Env      setting.Env       `yaml:"env" mapstructure:"env"`
+	Scanner  *ScannerConfig    `yaml:"scanner" mapstructure:"scanner"`
+	Cron     *CronConfig       `yaml:"cron" mapstructure:"cron"`
+	Output   *OutputConfig     `yaml:"output" mapstructure:"output"`
+	Notifier *NotifierConfig   `yaml:"notifier" mapstructure:"notifier"`
+	Logger   *log.LoggerConfig `yaml:"logger" mapstructure:"logger"`
 }
 
 type ScannerConfig struct {
@@ -25,6 +27,180 @@ type ScannerConfig struct {
 	RequestIntervalMs int      `yaml:"request_interval_ms" mapstructure:"request_interval_ms"`
 	MaxConcurrent     int      `yaml:"max_concurrent" mapstructure:"max_concurrent"`
 	UserAgent         string   `yaml:"user_agent" mapstructure:"user_agent"`
+
+	SeedURLs []string `yaml:"seed_urls" mapstructure:"seed_urls"` // URL 列表，每个 URL 以深度 0 扫描且不跟随链接
+	SeedFile string   `yaml:"seed_file" mapstructure:"seed_file"` // URL 列表文件，每行一个 URL，# 开头为注释，"-" 表示从标准输入读取
+	SeedOnly bool     `yaml:"seed_only" mapstructure:"seed_only"` // 仅扫描 URL 列表，不从 target_url 开始爬取
+
+	Headers map[string]string `yaml:"headers" mapstructure:"headers"` // 自定义请求头
+	Cookies []*CookieConfig   `yaml:"cookies" mapstructure:"cookies"` // 静态 Cookie
+	Auth    *AuthConfig       `yaml:"auth" mapstructure:"auth"`       // HTTP 认证
+	Login   *LoginConfig      `yaml:"login" mapstructure:"login"`     // 表单登录
+
+	Transport *TransportConfig `yaml:"transport" mapstructure:"transport"`   // HTTP 传输层配置（代理、TLS、连接池）
+	BodyLimit *BodyLimitConfig `yaml:"body_limit" mapstructure:"body_limit"` // 响应体大小限制与流式处理
+	Local     *LocalConfig     `yaml:"local" mapstructure:"local"`           // 本地目录扫描（target_url 为 file:// 或目录路径时生效）
+	Offline   *OfflineConfig   `yaml:"offline" mapstructure:"offline"`       // 离线重扫
+	Normalize *NormalizeConfig `yaml:"normalize" mapstructure:"normalize"`   // 文本规范化，对抗零宽字符、全角、形近字母等混淆
+
+	KeywordRules []*KeywordRuleConfig `yaml:"keyword_rules" mapstructure:"keyword_rules"` // 按关键词设置的匹配选项
+	Detectors    []string             `yaml:"detectors" mapstructure:"detectors"`         // 启用的敏感数据检测器，如 cn_id_card、cn_mobile、bank_card、email、aws_key、private_key、jwt
+	Boilerplate  *BoilerplateConfig   `yaml:"boilerplate" mapstructure:"boilerplate"`     // 站点公共区块（页头、导航、页脚）识别
+	Exemptions   []*ExemptionConfig   `yaml:"exemptions" mapstructure:"exemptions"`       // 已确认可接受的命中，不计入报告正文
+
+	Locale *LocaleConfig `yaml:"locale" mapstructure:"locale"` // 报告、通知及附加发现使用的语言与时区
+}
+
+type LocaleConfig struct {
+	Locale   string `yaml:"locale" mapstructure:"locale"`     // zh-CN 或 en-US，默认 zh-CN
+	Timezone string `yaml:"timezone" mapstructure:"timezone"` // IANA 时区名，如 Asia/Shanghai、America/New_York，默认使用系统时区
+}
+
+// Resolve 返回语言与时区，未设置的项沿用 fallback
+func (c *LocaleConfig) Resolve(fallback *LocaleConfig) (locale, timezone string) {
+	if c != nil {
+		locale, timezone = c.Locale, c.Timezone
+	}
+	if fallback != nil {
+		if locale == "" {
+			locale = fallback.Locale
+		}
+		if timezone == "" {
+			timezone = fallback.Timezone
+		}
+	}
+	return locale, timezone
+}
+
+// ExemptionConfig 豁免规则，匹配的命中不出现在报告正文与通知中，只在“已豁免”部分列出
+type ExemptionConfig struct {
+	URL         string `yaml:"url" mapstructure:"url"`                   // 页面 URL，* 匹配任意字符
+	Keyword     string `yaml:"keyword" mapstructure:"keyword"`           // 为空时豁免所有关键词
+	SnippetHash string `yaml:"snippet_hash" mapstructure:"snippet_hash"` // 只豁免上下文片段哈希相同的命中，哈希见报告中的命中片段
+	Expires     string `yaml:"expires" mapstructure:"expires"`           // 有效期至（含当天），格式 2006-01-02，为空表示长期有效
+	Reason      string `yaml:"reason" mapstructure:"reason"`             // 豁免原因
+}
+
+type BoilerplateConfig struct {
+	Enabled  bool    `yaml:"enabled" mapstructure:"enabled"`     // 将公共区块中的命中汇总为一条附加发现，页面结果只保留正文中的命中
+	MinRatio float64 `yaml:"min_ratio" mapstructure:"min_ratio"` // 区块出现在不少于该比例的 HTML 页面中时视为公共区块，默认 0.5
+	MinPages int     `yaml:"min_pages" mapstructure:"min_pages"` // 同时至少出现在该数量的页面中，默认 5
+}
+
+// KeywordRuleConfig 单个关键词的匹配选项，keyword 需同时出现在 keywords 中
+type KeywordRuleConfig struct {
+	Keyword         string `yaml:"keyword" mapstructure:"keyword"`
+	ChineseVariants bool   `yaml:"chinese_variants" mapstructure:"chinese_variants"` // 同时匹配繁体、简体写法
+	Pinyin          bool   `yaml:"pinyin" mapstructure:"pinyin"`                     // 同时匹配全拼，如 "dubo"
+	PinyinInitials  bool   `yaml:"pinyin_initials" mapstructure:"pinyin_initials"`   // 同时匹配拼音首字母，如 "db"
+	MaxDistance     int    `yaml:"max_distance" mapstructure:"max_distance"`         // 模糊匹配允许的最大编辑距离（按字符计，相邻字符交换计为一次），0 表示不启用
+}
+
+// SecretConfig 敏感信息配置，按 value、env、file 的顺序取第一个非空来源
+type SecretConfig struct {
+	Value string `yaml:"value" mapstructure:"value"` // 明文值（不推荐）
+	Env   string `yaml:"env" mapstructure:"env"`     // 环境变量名
+	File  string `yaml:"file" mapstructure:"file"`   // 文件路径，读取后去除首尾空白
+}
+
+type CookieConfig struct {
+	Name   string `yaml:"name" mapstructure:"name"`
+	Value  string `yaml:"value" mapstructure:"value"`
+	Domain string `yaml:"domain" mapstructure:"domain"` // 为空时使用目标网站域名
+	Path   string `yaml:"path" mapstructure:"path"`     // 为空时为 /
+}
+
+type AuthConfig struct {
+	Type     string        `yaml:"type" mapstructure:"type"`         // basic / bearer
+	Username string        `yaml:"username" mapstructure:"username"` // basic 认证用户名
+	Password *SecretConfig `yaml:"password" mapstructure:"password"` // basic 认证密码
+	Token    *SecretConfig `yaml:"token" mapstructure:"token"`       // bearer token
+}
+
+type LoginConfig struct {
+	Enabled      bool               `yaml:"enabled" mapstructure:"enabled"`             // 是否启用表单登录
+	FormURL      string             `yaml:"form_url" mapstructure:"form_url"`           // 登录表单页面，用于提取隐藏字段（如 CSRF token），可选
+	URL          string             `yaml:"url" mapstructure:"url"`                     // 表单提交地址，为空时使用表单页面中 form 的 action
+	Method       string             `yaml:"method" mapstructure:"method"`               // 提交方法，默认 POST
+	Fields       []*FormFieldConfig `yaml:"fields" mapstructure:"fields"`               // 表单字段
+	SuccessCheck *LoginCheckConfig  `yaml:"success_check" mapstructure:"success_check"` // 登录成功判定，所有条件都需满足
+	LogoutCheck  *LoginCheckConfig  `yaml:"logout_check" mapstructure:"logout_check"`   // 登出判定，任一条件满足即重新登录
+	MaxRelogin   int                `yaml:"max_relogin" mapstructure:"max_relogin"`     // 单次扫描最多重新登录次数，默认 3
+	SkipURLs     []string           `yaml:"skip_urls" mapstructure:"skip_urls"`         // 不爬取包含这些片段的 URL（如登出链接）
+}
+
+type FormFieldConfig struct {
+	Name   string        `yaml:"name" mapstructure:"name"`
+	Value  string        `yaml:"value" mapstructure:"value"`
+	Secret *SecretConfig `yaml:"secret" mapstructure:"secret"` // 设置时优先于 value
+}
+
+type LoginCheckConfig struct {
+	StatusCodes  []int  `yaml:"status_codes" mapstructure:"status_codes"`   // 响应状态码
+	URLContains  string `yaml:"url_contains" mapstructure:"url_contains"`   // 最终 URL（跟随重定向后）包含
+	BodyContains string `yaml:"body_contains" mapstructure:"body_contains"` // 响应内容包含
+	Cookie       string `yaml:"cookie" mapstructure:"cookie"`               // Cookie 名称，成功判定时要求存在，登出判定时要求缺失
+}
+
+type TransportConfig struct {
+	Proxy               *ProxyConfig `yaml:"proxy" mapstructure:"proxy"`
+	TLS                 *TLSConfig   `yaml:"tls" mapstructure:"tls"`
+	MaxIdleConns        int          `yaml:"max_idle_conns" mapstructure:"max_idle_conns"`                   // 最大空闲连接数，默认 100
+	MaxIdleConnsPerHost int          `yaml:"max_idle_conns_per_host" mapstructure:"max_idle_conns_per_host"` // 每个主机最大空闲连接数，默认与 max_concurrent 一致
+	MaxConnsPerHost     int          `yaml:"max_conns_per_host" mapstructure:"max_conns_per_host"`           // 每个主机最大连接数，0 表示不限制
+	IdleConnTimeoutMs   int          `yaml:"idle_conn_timeout_ms" mapstructure:"idle_conn_timeout_ms"`       // 空闲连接超时（毫秒），默认 90000
+	DisableHTTP2        bool         `yaml:"disable_http2" mapstructure:"disable_http2"`                     // 禁用 HTTP/2
+}
+
+type ProxyConfig struct {
+	URL             string        `yaml:"url" mapstructure:"url"`                           // 代理地址，支持 http:// https:// socks5:// socks5h://
+	HTTPSURL        string        `yaml:"https_url" mapstructure:"https_url"`               // HTTPS 请求使用的代理地址，为空时使用 url
+	FromEnvironment bool          `yaml:"from_environment" mapstructure:"from_environment"` // 使用 HTTP_PROXY / HTTPS_PROXY / NO_PROXY 环境变量，未配置代理地址时同样使用
+	Disabled        bool          `yaml:"disabled" mapstructure:"disabled"`                 // 不使用任何代理，包括环境变量
+	NoProxy         []string      `yaml:"no_proxy" mapstructure:"no_proxy"`                 // 不走代理的主机（支持 .example.com 后缀匹配）
+	Username        string        `yaml:"username" mapstructure:"username"`                 // 代理认证用户名
+	Password        *SecretConfig `yaml:"password" mapstructure:"password"`                 // 代理认证密码
+}
+
+type TLSConfig struct {
+	CAFiles            []string          `yaml:"ca_files" mapstructure:"ca_files"`                         // 额外信任的 CA 证书（PEM）
+	CertFile           string            `yaml:"cert_file" mapstructure:"cert_file"`                       // 客户端证书（PEM）
+	KeyFile            string            `yaml:"key_file" mapstructure:"key_file"`                         // 客户端私钥（PEM）
+	InsecureSkipVerify bool              `yaml:"insecure_skip_verify" mapstructure:"insecure_skip_verify"` // 跳过证书校验，仅用于测试环境
+	ServerName         string            `yaml:"server_name" mapstructure:"server_name"`                   // 覆盖 SNI 主机名
+	MinVersion         string            `yaml:"min_version" mapstructure:"min_version"`                   // 最低 TLS 版本：1.0 / 1.1 / 1.2 / 1.3
+	CertExpiry         *CertExpiryConfig `yaml:"cert_expiry" mapstructure:"cert_expiry"`                   // 证书有效期检查
+}
+
+type CertExpiryConfig struct {
+	Enabled  bool `yaml:"enabled" mapstructure:"enabled"`     // 是否报告证书有效期
+	WarnDays int  `yaml:"warn_days" mapstructure:"warn_days"` // 剩余天数低于该值时提升严重程度，默认 30
+}
+
+type BodyLimitConfig struct {
+	DefaultMaxBytes    int64            `yaml:"default_max_bytes" mapstructure:"default_max_bytes"`       // 默认最大响应体字节数（解压后），默认 10MB
+	Rules              []*BodyLimitRule `yaml:"rules" mapstructure:"rules"`                               // 按内容类型设置上限
+	StreamContentTypes []string         `yaml:"stream_content_types" mapstructure:"stream_content_types"` // 以流式方式匹配的非 HTML 内容类型
+}
+
+type BodyLimitRule struct {
+	ContentType string `yaml:"content_type" mapstructure:"content_type"` // 内容类型，支持 text/* 形式的通配
+	MaxBytes    int64  `yaml:"max_bytes" mapstructure:"max_bytes"`       // 最大字节数
+}
+
+type LocalConfig struct {
+	Extensions  []string `yaml:"extensions" mapstructure:"extensions"`     // 扫描的文件扩展名，默认 .html .htm .xhtml .txt .md .xml .json
+	ExcludeDirs []string `yaml:"exclude_dirs" mapstructure:"exclude_dirs"` // 跳过的目录名，默认 .git node_modules
+}
+
+type OfflineConfig struct {
+	Enabled bool   `yaml:"enabled" mapstructure:"enabled"` // 启用后从归档读取页面，不访问网站
+	Source  string `yaml:"source" mapstructure:"source"`   // WARC 文件（.warc / .warc.gz），为目录时使用其中最新的 WARC 文件
+}
+
+type NormalizeConfig struct {
+	Enabled       bool `yaml:"enabled" mapstructure:"enabled"`               // 匹配前对页面文本做 NFKC、去除零宽字符、形近字母映射
+	MaxSeparators int  `yaml:"max_separators" mapstructure:"max_separators"` // 关键词相邻字符之间最多允许的空白或标点数，0 表示不允许
 }
 
 type CronConfig struct {
@@ -33,13 +209,72 @@ type CronConfig struct {
 }
 
 type OutputConfig struct {
-	Dir        string `yaml:"dir" mapstructure:"dir"`
-	FilePrefix string `yaml:"file_prefix" mapstructure:"file_prefix"`
+	Dir        string            `yaml:"dir" mapstructure:"dir"`
+	FilePrefix string            `yaml:"file_prefix" mapstructure:"file_prefix"`
+	Formats    []string          `yaml:"formats" mapstructure:"formats"`     // 报告格式：txt、json、jsonl、csv、markdown、html、xlsx，默认 txt
+	Templates  []*TemplateConfig `yaml:"templates" mapstructure:"templates"` // 自定义报告模板
+	WARC       *WARCConfig       `yaml:"warc" mapstructure:"warc"`           // WARC 归档
+	Stream     *StreamConfig     `yaml:"stream" mapstructure:"stream"`       // 结果流
+	Evidence   *EvidenceConfig   `yaml:"evidence" mapstructure:"evidence"`   // 证据快照
+	Diff       *DiffConfig       `yaml:"diff" mapstructure:"diff"`           // 与上一次扫描比较
+	Trend      *TrendConfig      `yaml:"trend" mapstructure:"trend"`         // 扫描统计走势
+	History    *HistoryConfig    `yaml:"history" mapstructure:"history"`     // 扫描历史
+	Retention  *RetentionConfig  `yaml:"retention" mapstructure:"retention"` // 输出目录清理
+}
+
+type TemplateConfig struct {
+	Name      string `yaml:"name" mapstructure:"name"`           // 模板名称，出现在报告文件名中：{前缀}_{时间}.{name}.{extension}
+	File      string `yaml:"file" mapstructure:"file"`           // 模板文件路径
+	Engine    string `yaml:"engine" mapstructure:"engine"`       // text 或 html，默认按模板文件扩展名判断（.html/.htm 为 html）
+	Extension string `yaml:"extension" mapstructure:"extension"` // 报告扩展名，默认取模板文件去掉 .tmpl 后的扩展名
+}
+
+type RetentionConfig struct {
+	Enabled           bool   `yaml:"enabled" mapstructure:"enabled"`                         // 是否在每次扫描后清理输出目录
+	KeepLast          int    `yaml:"keep_last" mapstructure:"keep_last"`                     // 保留最近 N 次扫描
+	KeepDaily         int    `yaml:"keep_daily" mapstructure:"keep_daily"`                   // 最近 N 天每天保留最后一次扫描
+	KeepWeekly        int    `yaml:"keep_weekly" mapstructure:"keep_weekly"`                 // 最近 N 周每周保留最后一次扫描
+	CompressAfterDays int    `yaml:"compress_after_days" mapstructure:"compress_after_days"` // 早于 N 天的报告与证据快照压缩归档，0 表示不压缩
+	CompressFormat    string `yaml:"compress_format" mapstructure:"compress_format"`         // gzip：逐个文件压缩；zip：每次扫描打包为一个文件。默认 gzip
+	MaxSizeMB         int    `yaml:"max_size_mb" mapstructure:"max_size_mb"`                 // 输出目录总大小上限（MB），超出时从最早的扫描开始删除，0 表示不限
+}
+
+type DiffConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"` // 是否与同一目标的上一次扫描比较，在报告中列出变化，需开启扫描历史
+}
+
+type TrendConfig struct {
+	Enabled          bool    `yaml:"enabled" mapstructure:"enabled"`                     // 是否在报告与通知中展示同一目标的统计走势，需开启扫描历史
+	Days             int     `yaml:"days" mapstructure:"days"`                           // 统计最近 N 天的扫描，默认 30
+	MaxPoints        int     `yaml:"max_points" mapstructure:"max_points"`               // 走势中最多展示的扫描次数，默认 30
+	AverageDays      int     `yaml:"average_days" mapstructure:"average_days"`           // 与最近 N 天的平均值比较，默认 7
+	ThresholdPercent float64 `yaml:"threshold_percent" mapstructure:"threshold_percent"` // 相对平均值的变化达到该百分比时在通知中提示，默认 20
+}
+
+type HistoryConfig struct {
+	Enabled bool   `yaml:"enabled" mapstructure:"enabled"` // 是否记录每次扫描的完整结果
+	Dir     string `yaml:"dir" mapstructure:"dir"`         // 历史目录，默认 {output.dir}/history
+}
+
+type StreamConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"` // 是否在扫描过程中将每个页面的结果追加写入 JSONL 文件
+}
+
+type EvidenceConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"` // 是否为命中关键词的页面保存证据快照
+}
+
+type WARCConfig struct {
+	Enabled  bool   `yaml:"enabled" mapstructure:"enabled"`   // 是否将抓取的响应写入 WARC 1.1 文件
+	Mode     string `yaml:"mode" mapstructure:"mode"`         // all：所有响应；hits：仅命中关键词或检出敏感数据的响应。默认 all
+	Compress bool   `yaml:"compress" mapstructure:"compress"` // 是否 gzip 压缩（.warc.gz）
 }
 
 type NotifierConfig struct {
 	Enabled       bool                  `yaml:"enabled" mapstructure:"enabled"`               // 是否启用通知
+	OnlyChanges   bool                  `yaml:"only_changes" mapstructure:"only_changes"`     // 仅在出现新增命中或新增附加发现时通知，需开启 output.diff
 	WechatWebhook *wechat.WebhookConfig `yaml:"wechat_webhook" mapstructure:"wechat_webhook"` // 企业微信 Webhook 配置
+	Locale        *LocaleConfig         `yaml:"locale" mapstructure:"locale"`                 // 通知使用的语言与时区，未设置的项沿用 scanner.locale
 }
 
 func (c *Config) LoadConfig() {
@@ -49,11 +284,33 @@ func (c *Config) LoadConfig() {
 	}
 
 	log.Info("LoadConfig", log.Any("config", c))
+	// 热更新时无法拒绝新配置，只记录错误，依赖扫描历史的功能在扫描时跳过
+	if err := c.Validate(); err != nil {
+		log.Error("invalid config", log.Err(err))
+	}
+}
+
+// Validate 检查配置项之间的依赖关系
+func (c *Config) Validate() error {
+	if c.Output == nil {
+		return nil
+	}
+	historyEnabled := c.Output.History != nil && c.Output.History.Enabled
+	if c.Output.Diff != nil && c.Output.Diff.Enabled && !historyEnabled {
+		return errors.New("output.diff requires output.history to be enabled")
+	}
+	if c.Output.Trend != nil && c.Output.Trend.Enabled && !historyEnabled {
+		return errors.New("output.trend requires output.history to be enabled")
+	}
+	return nil
 }
 
 func NewConfig(cfgOption *hotcfg.LocalConfigOption) (config *Config, err error) {
 	config = &Config{}
 	config.BaseConfigCapable, err = hotcfg.NewLocalBaseConfigCapable(cfgOption)
 	config.LoadConfig()
+	if err == nil {
+		err = config.Validate()
+	}
 	return config, err
 }
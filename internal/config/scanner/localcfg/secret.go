@@ -0,0 +1,103 @@
+package localcfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Resolve 读取敏感信息的实际值，未配置任何来源时返回空字符串
+func (s *SecretConfig) Resolve() (string, error) {
+	if s == nil {
+		return "", nil
+	}
+
+	if s.Value != "" {
+		return s.Value, nil
+	}
+
+	if s.Env != "" {
+		value, ok := os.LookupEnv(s.Env)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", s.Env)
+		}
+		return value, nil
+	}
+
+	if s.File != "" {
+		content, err := os.ReadFile(s.File)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimSpace(string(content)), nil
+	}
+
+	return "", nil
+}
+
+// redactedValue 日志中代替敏感值输出的占位符
+const redactedValue = "******"
+
+// redact 非空值替换为占位符，便于在日志中区分“已配置”与“未配置”
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// redactURL 隐藏 URL 中的密码部分，无法解析时整体隐藏
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redactedValue
+	}
+	return u.Redacted()
+}
+
+// MarshalJSON 日志输出配置时隐藏明文值，env 与 file 只是来源名称，原样输出
+func (s SecretConfig) MarshalJSON() ([]byte, error) {
+	type plain SecretConfig
+	s.Value = redact(s.Value)
+	return json.Marshal(plain(s))
+}
+
+// MarshalJSON 日志输出配置时隐藏 Cookie 值
+func (c CookieConfig) MarshalJSON() ([]byte, error) {
+	type plain CookieConfig
+	c.Value = redact(c.Value)
+	return json.Marshal(plain(c))
+}
+
+// MarshalJSON 日志输出配置时隐藏表单字段值，登录表单中的字段大多是账号密码
+func (f FormFieldConfig) MarshalJSON() ([]byte, error) {
+	type plain FormFieldConfig
+	f.Value = redact(f.Value)
+	return json.Marshal(plain(f))
+}
+
+// MarshalJSON 日志输出配置时隐藏代理地址中的密码
+func (p ProxyConfig) MarshalJSON() ([]byte, error) {
+	type plain ProxyConfig
+	p.URL = redactURL(p.URL)
+	p.HTTPSURL = redactURL(p.HTTPSURL)
+	return json.Marshal(plain(p))
+}
+
+// MarshalJSON 日志输出配置时隐藏请求头的值，如 Authorization、Cookie
+func (c ScannerConfig) MarshalJSON() ([]byte, error) {
+	type plain ScannerConfig
+	if len(c.Headers) > 0 {
+		headers := make(map[string]string, len(c.Headers))
+		for name, value := range c.Headers {
+			headers[name] = redact(value)
+		}
+		c.Headers = headers
+	}
+	return json.Marshal(plain(c))
+}
@@ -0,0 +1,35 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCatalogKeys 各语言的消息目录必须包含相同的键，且格式化参数个数一致
+func TestCatalogKeys(t *testing.T) {
+	for locale, catalog := range catalogs {
+		if locale == LocaleZhCN {
+			continue
+		}
+		for key, text := range zhCN {
+			translated, ok := catalog[key]
+			if !ok {
+				t.Errorf("%s: missing key %q", locale, key)
+				continue
+			}
+			if got, want := countVerbs(translated), countVerbs(text); got != want {
+				t.Errorf("%s: key %q has %d format verbs, want %d", locale, key, got, want)
+			}
+		}
+		for key := range catalog {
+			if _, ok := zhCN[key]; !ok {
+				t.Errorf("%s: key %q not found in %s", locale, key, LocaleZhCN)
+			}
+		}
+	}
+}
+
+// countVerbs 统计消息中的格式化动词个数，%% 不计入
+func countVerbs(text string) int {
+	return strings.Count(strings.ReplaceAll(text, "%%", ""), "%")
+}
@@ -0,0 +1,187 @@
+package i18n
+
+// enUS 英文消息目录
+var enUS = map[string]string{
+	// 报告
+	"report.title": "Key-Spy Scan Report",
+	"report.end":   "End of Report",
+
+	"section.scan_info":      "Scan Information",
+	"section.summary":        "Summary",
+	"section.diff":           "Changes Since Previous Scan",
+	"section.trend":          "Trend",
+	"section.findings":       "Additional Findings",
+	"section.errors":         "Scan Errors",
+	"section.suppressed":     "Suppressed Hits",
+	"section.results":        "Matched Pages",
+	"section.results_detail": "Match Details",
+
+	"label.item":                  "Item",
+	"label.value":                 "Value",
+	"label.target":                "Target",
+	"label.keywords":              "Keywords",
+	"label.keyword":               "Keyword",
+	"label.start_time":            "Start time",
+	"label.end_time":              "End time",
+	"label.scan_time":             "Scan time",
+	"label.duration":              "Duration",
+	"label.scan_mode":             "Scan mode",
+	"label.source_archive":        "Source archive",
+	"label.warc":                  "WARC archive",
+	"label.warc_record":           "WARC record",
+	"label.evidence":              "Evidence snapshot",
+	"label.total_pages":           "Pages scanned",
+	"label.match_pages":           "Pages with keywords",
+	"label.error_count":           "Scan errors",
+	"label.finding_count":         "Additional findings",
+	"label.suppressed_count":      "Suppressed hits",
+	"label.previous_scan":         "Previous scan",
+	"label.new_hits":              "New hits",
+	"label.resolved_hits":         "Resolved",
+	"label.changed_hits":          "Count changed",
+	"label.persisting_hits":       "Persisting",
+	"label.new_findings":          "New findings",
+	"label.new_finding":           "New finding",
+	"label.change":                "Change",
+	"label.change_since_previous": "Since previous scan",
+	"label.previous_count":        "Previous count",
+	"label.current_count":         "Current count",
+	"label.depth":                 "Depth",
+	"label.page_depth":            "Page depth",
+	"label.index":                 "No.",
+	"label.count":                 "Count",
+	"label.occurrences":           "Occurrences",
+	"label.total_count":           "Total occurrences",
+	"label.found_keywords":        "Keywords found",
+	"label.keyword_counts":        "Occurrences by keyword",
+	"label.variant":               "Matched form",
+	"label.variants":              "Matched variants",
+	"label.distance":              "Edit distance",
+	"label.location":              "Location",
+	"label.element":               "Element",
+	"label.offset":                "Offset",
+	"label.hidden":                "Hidden",
+	"label.hidden_reason":         "Hidden reason",
+	"label.hidden_hits":           "Hidden text hits",
+	"label.sensitive":             "Sensitive data",
+	"label.snippet":               "Snippet",
+	"label.snippet_hash":          "Snippet hash",
+	"label.severity":              "Severity",
+	"label.type":                  "Type",
+	"label.title":                 "Title",
+	"label.detail":                "Detail",
+	"label.error":                 "Error",
+	"label.reason":                "Reason",
+	"label.expires":               "Expires",
+	"label.total":                 "Total",
+	"label.yes":                   "Yes",
+	"label.review_status":         "Review status",
+	"label.review_note":           "Review note",
+	"label.metric":                "Metric",
+	"label.trend":                 "Trend",
+	"label.current":               "Current",
+	"label.average":               "%d-day average",
+
+	"scan_mode.online":         "Online crawl",
+	"scan_mode.local":          "Local directory",
+	"scan_mode.offline":        "Offline rescan",
+	"scan_mode.offline_detail": "Offline rescan (site not accessed)",
+	"scan_mode.offline_source": "Offline rescan (source %s)",
+
+	"change.new":        "New",
+	"change.changed":    "Changed",
+	"change.persisting": "Unchanged",
+
+	"diff.summary":        "New hits: %d, resolved: %d, count changed: %d, persisting: %d, new findings: %d",
+	"diff.previous_times": "previously %d time(s)",
+
+	"trend.summary":            "Last %d days: %d scans (%s to %s)",
+	"trend.metric.total_pages": "Pages scanned",
+	"trend.metric.match_pages": "Pages with keywords",
+	"trend.metric.hit_count":   "Total hits",
+	"trend.metric.error_rate":  "Error rate",
+	"trend.metric.duration":    "Scan duration",
+	"trend.metric.seconds":     "Scan duration (s)",
+	"trend.metric.keyword":     "Hits for \"%s\"",
+
+	"count.times":       "%d time(s)",
+	"count.places":      "%d",
+	"count.total_times": "%d time(s) in total",
+	"count.total_hits":  "%d hits in total",
+
+	"result.none":             "No pages containing the keywords were found.",
+	"result.truncated":        "Response body truncated: only the first %d bytes were scanned",
+	"result.snippets_limited": "%d hits in total, the first %d are listed here.",
+
+	"variant.label":       "%s (%s)",
+	"variant.label_fuzzy": "%s (%s, edit distance %d)",
+	"location.hidden":     "%s (hidden: %s)",
+
+	"txt.section":  "[%s]\n",
+	"txt.snippets": "Snippets (snippet hash in brackets, usable in exemption rules)",
+
+	"xlsx.sheet.summary": "Summary",
+	"xlsx.sheet.pages":   "Matched Pages",
+	"xlsx.sheet.hits":    "Hit Details",
+	"xlsx.sheet.diff":    "Changes",
+	"xlsx.sheet.trend":   "Trend",
+	"xlsx.sheet.pivot":   "Keyword Pivot",
+
+	"html.duration":           " (took %s)",
+	"html.statistics":         "Statistics",
+	"html.keyword_chart":      "Keyword occurrences",
+	"html.severity_chart":     "Findings by severity",
+	"html.no_keywords":        "No keywords found",
+	"html.no_findings":        "No additional findings",
+	"html.filter_placeholder": "Filter by URL or snippet",
+	"html.all_keywords":       "All keywords",
+	"html.only_hidden":        "Hidden text only",
+	"html.only_sensitive":     "Sensitive data only",
+	"html.filter_count":       "Showing {shown} / {total} pages",
+	"html.expand":             "Expand",
+	"html.collapse":           "Collapse",
+	"html.truncated":          "truncated",
+
+	"review.pending":        "Pending",
+	"review.confirmed":      "Confirmed",
+	"review.false_positive": "False positive",
+	"review.fixed":          "Fixed",
+	"review.accepted":       "Accepted",
+
+	"duration.less_than_second": "less than 1s",
+	"duration.hours":            "%dh",
+	"duration.minutes":          "%dm",
+	"duration.seconds":          "%ds",
+	"duration.separator":        " ",
+
+	// 通知
+	"notify.title_offline":    "Key-Spy Scan Report (offline rescan)",
+	"notify.match_pages":      "Pages containing keywords: %d",
+	"notify.no_match":         "No pages contain keywords",
+	"notify.suppressed":       "Suppressed hits: %d (see report)",
+	"notify.no_new_hits":      "No new hits",
+	"notify.resolved_changed": "Resolved: %d, count changed: %d",
+	"notify.total_new_hits":   "%d new hits in total",
+	"notify.trend_up":         "%[1]s up %[2]s vs %[3]d-day average (%[4]s → %[5]s)",
+	"notify.trend_down":       "%[1]s down %[2]s vs %[3]d-day average (%[4]s → %[5]s)",
+	"notify.top_results":      "Top 5 Matches",
+	"notify.hit_times":        "**%d** hit(s)",
+	"notify.more_results":     "See the full report for more results (%d in total)",
+	"notify.report_files":     "Report files",
+
+	// 附加发现
+	"finding.keyword_times":      "%s (%d time(s))",
+	"finding.boilerplate.title":  "Site-wide block contains keywords, found on %d pages",
+	"finding.boilerplate.detail": "Keywords: %s; block content: %s",
+	"finding.boilerplate.hidden": "; %d hits in hidden text",
+	"finding.hidden_text.title":  "Hidden text contains %d keyword occurrences, possible SEO spam",
+	"finding.hidden_text.hit":    "%s (%s, %s)",
+	"finding.sensitive.title":    "%d sensitive values detected (%s)",
+	"finding.cert.expires_in":    "Certificate expires in %d days",
+	"finding.cert.expired":       "Certificate has expired",
+	"finding.exemption.title":    "Exemption expired on %s, its hits are reported again",
+	"finding.exemption.detail":   "Keyword: %s; snippet hash: %s; reason: %s",
+	"finding.exemption.any":      "any",
+	"list.separator":             ", ",
+	"detail.separator":           "; ",
+}
@@ -0,0 +1,167 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 支持的语言
+const (
+	LocaleZhCN = "zh-CN"
+	LocaleEnUS = "en-US"
+
+	DefaultLocale = LocaleZhCN
+)
+
+// ReportTimeLayout 扫描报告中时间字段的格式，按本地时区记录
+const ReportTimeLayout = "2006-01-02 15:04:05"
+
+// catalogs 各语言的消息目录，缺少的条目回退到默认语言
+var catalogs = map[string]map[string]string{
+	LocaleZhCN: zhCN,
+	LocaleEnUS: enUS,
+}
+
+// timeLayouts 各语言的日期时间格式
+var timeLayouts = map[string]string{
+	LocaleZhCN: "2006-01-02 15:04:05",
+	LocaleEnUS: "Jan 2, 2006 3:04:05 PM",
+}
+
+// Printer 按语言与时区输出文本和时间
+type Printer struct {
+	locale   string
+	location *time.Location
+	showZone bool // 显式配置时区时在时间后标注时区
+}
+
+// NewPrinter 创建 Printer，locale 为空时使用 zh-CN，timezone 为 IANA 时区名，为空时使用系统时区
+func NewPrinter(locale, timezone string) (*Printer, error) {
+	p := &Printer{
+		locale:   DefaultLocale,
+		location: time.Local,
+	}
+	if locale != "" {
+		normalized, ok := normalizeLocale(locale)
+		if !ok {
+			return nil, fmt.Errorf("unsupported locale: %s, available: %s", locale, strings.Join(Locales(), ", "))
+		}
+		p.locale = normalized
+	}
+	if timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load timezone %s: %w", timezone, err)
+		}
+		p.location = location
+		p.showZone = true
+	}
+	return p, nil
+}
+
+// Default 返回使用默认语言与系统时区的 Printer
+func Default() *Printer {
+	return &Printer{locale: DefaultLocale, location: time.Local}
+}
+
+// Locales 返回支持的语言
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// normalizeLocale 忽略大小写并兼容 zh_CN、en 等写法
+func normalizeLocale(locale string) (string, bool) {
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	for name := range catalogs {
+		if strings.EqualFold(name, locale) {
+			return name, true
+		}
+	}
+	switch strings.ToLower(locale) {
+	case "zh", "zh-hans":
+		return LocaleZhCN, true
+	case "en":
+		return LocaleEnUS, true
+	}
+	return "", false
+}
+
+// Locale 返回语言名称，如 zh-CN
+func (p *Printer) Locale() string {
+	return p.locale
+}
+
+// T 返回消息目录中 key 对应的文本，args 非空时按 fmt.Sprintf 格式化；找不到时回退到默认语言，仍找不到时返回 key
+func (p *Printer) T(key string, args ...interface{}) string {
+	format, ok := catalogs[p.locale][key]
+	if !ok {
+		if format, ok = catalogs[DefaultLocale][key]; !ok {
+			format = key
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Time 按语言格式输出时间，显式配置时区时转换到该时区并标注时区
+func (p *Printer) Time(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	layout := timeLayouts[p.locale]
+	if p.showZone {
+		return t.In(p.location).Format(layout + " MST")
+	}
+	return t.In(p.location).Format(layout)
+}
+
+// ReportTime 转换扫描报告中的时间字符串，无法解析时原样返回
+func (p *Printer) ReportTime(value string) string {
+	t, err := time.ParseInLocation(ReportTimeLayout, value, time.Local)
+	if err != nil {
+		return value
+	}
+	return p.Time(t)
+}
+
+// Duration 将耗时格式化为易读的形式，如“1小时2分3秒”或 “1h 2m 3s”；参数可以是 time.Duration 或报告中的耗时字符串
+func (p *Printer) Duration(v interface{}) string {
+	var d time.Duration
+	switch value := v.(type) {
+	case time.Duration:
+		d = value
+	case string:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return value
+		}
+		d = parsed
+	default:
+		return fmt.Sprint(v)
+	}
+
+	d = d.Round(time.Second)
+	if d < time.Second {
+		return p.T("duration.less_than_second")
+	}
+	parts := make([]string, 0, 3)
+	if hours := int(d / time.Hour); hours > 0 {
+		parts = append(parts, p.T("duration.hours", hours))
+	}
+	if minutes := int(d % time.Hour / time.Minute); minutes > 0 {
+		parts = append(parts, p.T("duration.minutes", minutes))
+	}
+	if seconds := int(d % time.Minute / time.Second); seconds > 0 {
+		parts = append(parts, p.T("duration.seconds", seconds))
+	}
+	return strings.Join(parts, p.T("duration.separator"))
+}
@@ -0,0 +1,31 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gw-gong/key-spy/internal/pkg/model"
+)
+
+// TrendLabel 返回走势指标的名称，报告与通知共用
+func (p *Printer) TrendLabel(series *model.TrendSeries) string {
+	if series.Metric == model.TrendMetricKeyword {
+		return p.T("trend.metric.keyword", series.Keyword)
+	}
+	return p.T("trend.metric." + series.Metric)
+}
+
+// TrendValue 按指标格式化取值，错误率为百分比，耗时为易读的时长
+func (p *Printer) TrendValue(metric string, value float64) string {
+	switch metric {
+	case model.TrendMetricErrorRate:
+		return fmt.Sprintf("%.1f%%", value)
+	case model.TrendMetricDuration:
+		return p.Duration(time.Duration(value * float64(time.Second)))
+	}
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.1f", value)
+}
@@ -0,0 +1,187 @@
+package i18n
+
+// zhCN 简体中文消息目录，同时作为其他语言缺少条目时的回退
+var zhCN = map[string]string{
+	// 报告
+	"report.title": "Key-Spy 扫描报告",
+	"report.end":   "报告结束",
+
+	"section.scan_info":      "扫描信息",
+	"section.summary":        "统计摘要",
+	"section.diff":           "与上次扫描相比",
+	"section.trend":          "统计走势",
+	"section.findings":       "附加发现",
+	"section.errors":         "扫描错误",
+	"section.suppressed":     "已豁免命中",
+	"section.results":        "匹配结果",
+	"section.results_detail": "匹配结果详情",
+
+	"label.item":                  "项目",
+	"label.value":                 "内容",
+	"label.target":                "目标网站",
+	"label.keywords":              "搜索关键词",
+	"label.keyword":               "关键词",
+	"label.start_time":            "开始时间",
+	"label.end_time":              "结束时间",
+	"label.scan_time":             "扫描时间",
+	"label.duration":              "耗时",
+	"label.scan_mode":             "扫描模式",
+	"label.source_archive":        "来源归档",
+	"label.warc":                  "WARC 归档",
+	"label.warc_record":           "WARC 记录",
+	"label.evidence":              "证据快照",
+	"label.total_pages":           "扫描页面总数",
+	"label.match_pages":           "匹配关键词页面数",
+	"label.error_count":           "扫描错误数",
+	"label.finding_count":         "附加发现数",
+	"label.suppressed_count":      "已豁免命中数",
+	"label.previous_scan":         "上次扫描时间",
+	"label.new_hits":              "新增命中",
+	"label.resolved_hits":         "已消失",
+	"label.changed_hits":          "次数变化",
+	"label.persisting_hits":       "持续存在",
+	"label.new_findings":          "新增附加发现",
+	"label.new_finding":           "新增附加发现",
+	"label.change":                "变化",
+	"label.change_since_previous": "相对上次扫描",
+	"label.previous_count":        "上次次数",
+	"label.current_count":         "本次次数",
+	"label.depth":                 "深度",
+	"label.page_depth":            "页面深度",
+	"label.index":                 "序号",
+	"label.count":                 "次数",
+	"label.occurrences":           "出现次数",
+	"label.total_count":           "关键词总出现次数",
+	"label.found_keywords":        "出现的关键词",
+	"label.keyword_counts":        "各关键词统计",
+	"label.variant":               "命中写法",
+	"label.variants":              "命中的变体写法",
+	"label.distance":              "编辑距离",
+	"label.location":              "位置",
+	"label.element":               "元素",
+	"label.offset":                "偏移",
+	"label.hidden":                "隐藏",
+	"label.hidden_reason":         "隐藏原因",
+	"label.hidden_hits":           "隐藏文本命中",
+	"label.sensitive":             "敏感数据",
+	"label.snippet":               "片段",
+	"label.snippet_hash":          "片段哈希",
+	"label.severity":              "严重程度",
+	"label.type":                  "类型",
+	"label.title":                 "标题",
+	"label.detail":                "详情",
+	"label.error":                 "错误",
+	"label.reason":                "原因",
+	"label.expires":               "有效期至",
+	"label.total":                 "合计",
+	"label.yes":                   "是",
+	"label.review_status":         "审核状态",
+	"label.review_note":           "审核备注",
+	"label.metric":                "指标",
+	"label.trend":                 "走势",
+	"label.current":               "本次",
+	"label.average":               "近 %d 天平均",
+
+	"scan_mode.online":         "在线爬取",
+	"scan_mode.local":          "本地目录",
+	"scan_mode.offline":        "离线重扫",
+	"scan_mode.offline_detail": "离线重扫（未访问网站）",
+	"scan_mode.offline_source": "离线重扫（来源 %s）",
+
+	"change.new":        "新增",
+	"change.changed":    "有变化",
+	"change.persisting": "无变化",
+
+	"diff.summary":        "新增命中: %d，已消失: %d，次数变化: %d，持续存在: %d，新增附加发现: %d",
+	"diff.previous_times": "上次 %d 次",
+
+	"trend.summary":            "最近 %d 天共 %d 次扫描（%s 至 %s）",
+	"trend.metric.total_pages": "扫描页面数",
+	"trend.metric.match_pages": "匹配关键词页面数",
+	"trend.metric.hit_count":   "关键词总出现次数",
+	"trend.metric.error_rate":  "错误率",
+	"trend.metric.duration":    "扫描耗时",
+	"trend.metric.seconds":     "扫描耗时（秒）",
+	"trend.metric.keyword":     "“%s”出现次数",
+
+	"count.times":       "%d 次",
+	"count.places":      "%d 处",
+	"count.total_times": "共 %d 次",
+	"count.total_hits":  "共 %d 条明细",
+
+	"result.none":             "未找到包含关键词的页面。",
+	"result.truncated":        "响应体已截断: 仅扫描前 %d 字节",
+	"result.snippets_limited": "共 %d 条明细，此处列出前 %d 条。",
+
+	"variant.label":       "%s（%s）",
+	"variant.label_fuzzy": "%s（%s，编辑距离 %d）",
+	"location.hidden":     "%s（隐藏: %s）",
+
+	"txt.section":  "【%s】\n",
+	"txt.snippets": "命中片段（方括号内为片段哈希，可用于豁免规则）",
+
+	"xlsx.sheet.summary": "摘要",
+	"xlsx.sheet.pages":   "匹配页面",
+	"xlsx.sheet.hits":    "命中明细",
+	"xlsx.sheet.diff":    "变化",
+	"xlsx.sheet.trend":   "走势",
+	"xlsx.sheet.pivot":   "关键词透视",
+
+	"html.duration":           "（耗时 %s）",
+	"html.statistics":         "统计",
+	"html.keyword_chart":      "关键词出现次数",
+	"html.severity_chart":     "附加发现严重程度",
+	"html.no_keywords":        "未发现关键词",
+	"html.no_findings":        "无附加发现",
+	"html.filter_placeholder": "按 URL 或片段过滤",
+	"html.all_keywords":       "全部关键词",
+	"html.only_hidden":        "仅含隐藏文本",
+	"html.only_sensitive":     "仅含敏感数据",
+	"html.filter_count":       "显示 {shown} / {total} 个页面",
+	"html.expand":             "展开",
+	"html.collapse":           "收起",
+	"html.truncated":          "已截断",
+
+	"review.pending":        "待审核",
+	"review.confirmed":      "已确认",
+	"review.false_positive": "误报",
+	"review.fixed":          "已整改",
+	"review.accepted":       "可接受",
+
+	"duration.less_than_second": "不到 1 秒",
+	"duration.hours":            "%d小时",
+	"duration.minutes":          "%d分",
+	"duration.seconds":          "%d秒",
+	"duration.separator":        "",
+
+	// 通知
+	"notify.title_offline":    "Key-Spy 扫描报告（离线重扫）",
+	"notify.match_pages":      "发现 %d 个页面包含关键词",
+	"notify.no_match":         "未发现包含关键词的页面",
+	"notify.suppressed":       "已豁免命中: %d 次（详见报告）",
+	"notify.no_new_hits":      "无新增命中",
+	"notify.resolved_changed": "已消失: %d 处，次数变化: %d 处",
+	"notify.total_new_hits":   "共 %d 处新增命中",
+	"notify.trend_up":         "%[1]s比近 %[3]d 天平均值上升 %[2]s（%[4]s → %[5]s）",
+	"notify.trend_down":       "%[1]s比近 %[3]d 天平均值下降 %[2]s（%[4]s → %[5]s）",
+	"notify.top_results":      "匹配结果 TOP5",
+	"notify.hit_times":        "命中 **%d** 次",
+	"notify.more_results":     "更多结果请查看完整报告（共 %d 条）",
+	"notify.report_files":     "报告文件",
+
+	// 附加发现
+	"finding.keyword_times":      "%s（%d 次）",
+	"finding.boilerplate.title":  "站点公共区块包含关键词，出现在 %d 个页面",
+	"finding.boilerplate.detail": "关键词：%s；区块内容：%s",
+	"finding.boilerplate.hidden": "；其中隐藏文本命中 %d 次",
+	"finding.hidden_text.title":  "页面隐藏文本中包含 %d 处关键词，疑似 SEO 垃圾内容",
+	"finding.hidden_text.hit":    "%s（%s，%s）",
+	"finding.sensitive.title":    "检测到 %d 处敏感数据（%s）",
+	"finding.cert.expires_in":    "证书剩余 %d 天过期",
+	"finding.cert.expired":       "证书已过期",
+	"finding.exemption.title":    "豁免规则已于 %s 过期，相关命中重新计入报告",
+	"finding.exemption.detail":   "关键词：%s；片段哈希：%s；原因：%s",
+	"finding.exemption.any":      "全部",
+	"list.separator":             "、",
+	"detail.separator":           "；",
+}
@@ -0,0 +1,27 @@
+package model
+
+// ScanDiff 表示本次扫描与同一目标上一次扫描相比的变化
+type ScanDiff struct {
+	PreviousStartTime string       `json:"previous_start_time"`     // 上一次扫描的开始时间
+	NewHits           []*HitChange `json:"new_hits,omitempty"`      // 新出现的页面关键词
+	ResolvedHits      []*HitChange `json:"resolved_hits,omitempty"` // 已消失的页面关键词
+	ChangedHits       []*HitChange `json:"changed_hits,omitempty"`  // 出现次数发生变化的页面关键词
+	PersistingCount   int          `json:"persisting_count"`        // 两次扫描均出现的页面关键词数（含次数变化）
+	NewFindings       []*Finding   `json:"new_findings,omitempty"`  // 新出现的附加发现
+}
+
+// HitChange 表示某个页面上一个关键词的出现次数变化
+type HitChange struct {
+	URL      string `json:"url"`      // 页面 URL
+	Keyword  string `json:"keyword"`  // 关键词
+	Previous int    `json:"previous"` // 上一次扫描的出现次数
+	Current  int    `json:"current"`  // 本次扫描的出现次数
+}
+
+// 页面相对上一次扫描的变化
+const (
+	ChangeNew        = "new"        // 上一次扫描未命中
+	ChangeChanged    = "changed"    // 关键词或出现次数有变化
+	ChangePersisting = "persisting" // 与上一次扫描相同
+	ChangeResolved   = "resolved"   // 上一次扫描命中、本次已消失，只出现在 CSV 报告的变化行中
+)
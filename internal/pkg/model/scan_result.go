@@ -2,23 +2,135 @@ package model
 
 // ScanResult 表示单个页面的扫描结果
 type ScanResult struct {
-	URL           string            `json:"url"`            // 页面 URL
-	KeywordCounts map[string]int    `json:"keyword_counts"` // 关键词出现次数
-	TotalCount    int               `json:"total_count"`    // 总出现次数
-	Keywords      []string          `json:"keywords"`       // 出现的关键词列表
-	Depth         int               `json:"depth"`          // 页面深度
-	Error         string            `json:"error,omitempty"` // 错误信息（如有）
+	URL           string          `json:"url"`                      // 页面 URL
+	KeywordCounts map[string]int  `json:"keyword_counts"`           // 关键词出现次数
+	TotalCount    int             `json:"total_count"`              // 总出现次数
+	Keywords      []string        `json:"keywords"`                 // 出现的关键词列表
+	Depth         int             `json:"depth"`                    // 页面深度
+	Error         string          `json:"error,omitempty"`          // 错误信息（如有）
+	ContentType   string          `json:"content_type,omitempty"`   // 内容类型
+	BodySize      int64           `json:"body_size,omitempty"`      // 读取的响应体字节数（解压后）
+	Truncated     bool            `json:"truncated,omitempty"`      // 响应体是否因超出上限被截断
+	WARCRecordID  string          `json:"warc_record_id,omitempty"` // WARC 归档中响应记录的 ID
+	SHA256        string          `json:"sha256,omitempty"`         // 页面内容的 SHA-256 摘要
+	SnapshotDir   string          `json:"snapshot_dir,omitempty"`   // 证据快照目录
+	Hits          []*KeywordHit   `json:"hits,omitempty"`           // 命中明细
+	HiddenCount   int             `json:"hidden_count,omitempty"`   // 隐藏文本中的命中次数
+	Sensitive     []*SensitiveHit `json:"sensitive,omitempty"`      // 检测到的敏感数据
+	Change        string          `json:"change,omitempty"`         // 相对上一次扫描的变化
 }
 
+// SensitiveHit 表示页面中检测到的一处敏感数据，只保留脱敏后的值
+type SensitiveHit struct {
+	Detector string `json:"detector"` // 检测器名称
+	Value    string `json:"value"`    // 脱敏后的值
+	Offset   int    `json:"offset"`   // 在页面内容中的字节偏移
+}
+
+// KeywordHit 表示页面中的一次关键词命中
+type KeywordHit struct {
+	Keyword      string `json:"keyword"`                 // 命中的关键词
+	Variant      string `json:"variant,omitempty"`       // 命中的繁简、拼音写法或模糊命中的原文，直接命中关键词时为空
+	Distance     int    `json:"distance,omitempty"`      // 模糊命中的编辑距离
+	Offset       int    `json:"offset"`                  // 在页面内容中的字节偏移
+	Location     string `json:"location"`                // 命中位置
+	Element      string `json:"element,omitempty"`       // 所在元素路径
+	Snippet      string `json:"snippet,omitempty"`       // 上下文片段
+	SnippetHash  string `json:"snippet_hash,omitempty"`  // 上下文片段的哈希，用于豁免规则
+	Hidden       bool   `json:"hidden,omitempty"`        // 是否位于隐藏文本中
+	HiddenReason string `json:"hidden_reason,omitempty"` // 隐藏原因
+}
+
+// 命中位置
+const (
+	HitLocationText      = "text"      // 页面正文
+	HitLocationAttribute = "attribute" // 标签属性
+	HitLocationScript    = "script"    // 脚本
+	HitLocationStyle     = "style"     // 样式表
+	HitLocationComment   = "comment"   // HTML 注释
+)
+
 // ScanReport 表示完整的扫描报告
 type ScanReport struct {
-	TargetURL   string        `json:"target_url"`   // 目标网站
-	Keywords    []string      `json:"keywords"`     // 搜索的关键词列表
-	StartTime   string        `json:"start_time"`   // 开始时间
-	EndTime     string        `json:"end_time"`     // 结束时间
-	Duration    string        `json:"duration"`     // 耗时
-	TotalPages  int           `json:"total_pages"`  // 扫描的总页面数
-	MatchPages  int           `json:"match_pages"`  // 匹配的页面数
-	Results     []*ScanResult `json:"results"`      // 匹配的结果
-	ErrorCount  int           `json:"error_count"`  // 错误数
+	TargetURL   string         `json:"target_url"`             // 目标网站
+	Keywords    []string       `json:"keywords"`               // 搜索的关键词列表
+	StartTime   string         `json:"start_time"`             // 开始时间
+	EndTime     string         `json:"end_time"`               // 结束时间
+	Duration    string         `json:"duration"`               // 耗时
+	TotalPages  int            `json:"total_pages"`            // 扫描的总页面数
+	MatchPages  int            `json:"match_pages"`            // 匹配的页面数
+	Results     []*ScanResult  `json:"results"`                // 匹配的结果
+	ErrorCount  int            `json:"error_count"`            // 错误数
+	Errors      []*ScanError   `json:"errors,omitempty"`       // 抓取或解析失败的页面
+	Findings    []*Finding     `json:"findings,omitempty"`     // 附加发现
+	WARCFile    string         `json:"warc_file,omitempty"`    // WARC 归档文件路径，离线重扫时为读取的来源文件
+	StreamFile  string         `json:"stream_file,omitempty"`  // 结果流文件路径
+	ScanMode    string         `json:"scan_mode,omitempty"`    // 扫描模式
+	EvidenceDir string         `json:"evidence_dir,omitempty"` // 证据快照目录
+	Suppressed  []*Suppression `json:"suppressed,omitempty"`   // 被豁免规则隐藏的命中
+	Diff        *ScanDiff      `json:"diff,omitempty"`         // 与上一次扫描相比的变化，没有可比较的扫描时为空
+	Trend       *ScanTrend     `json:"trend,omitempty"`        // 最近若干次扫描的统计走势
+
+	FetchedURLs map[string]bool `json:"-"` // 本次成功抓取的页面，用于判断上一次的命中是否已消失
+}
+
+// SuppressedCount 统计被豁免的命中次数
+func (r *ScanReport) SuppressedCount() int {
+	count := 0
+	for _, s := range r.Suppressed {
+		count += s.Count
+	}
+	return count
+}
+
+// ScanError 表示抓取或解析失败的页面
+type ScanError struct {
+	URL   string `json:"url"`   // 页面 URL
+	Depth int    `json:"depth"` // 页面深度
+	Error string `json:"error"` // 错误信息
+}
+
+// Suppression 表示页面中被豁免规则隐藏的命中
+type Suppression struct {
+	URL         string `json:"url"`                    // 页面 URL
+	Keyword     string `json:"keyword"`                // 关键词
+	Count       int    `json:"count"`                  // 被豁免的命中次数
+	SnippetHash string `json:"snippet_hash,omitempty"` // 规则指定的片段哈希
+	Reason      string `json:"reason,omitempty"`       // 豁免原因
+	Expires     string `json:"expires,omitempty"`      // 规则有效期
+}
+
+// 扫描模式
+const (
+	ScanModeOnline  = "online"  // 在线爬取网站
+	ScanModeLocal   = "local"   // 扫描本地目录
+	ScanModeOffline = "offline" // 基于 WARC 归档的离线重扫
+)
+
+// 严重程度
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// 附加发现类型
+const (
+	FindingTypeCertExpiry  = "cert_expiry" // TLS 证书有效期
+	FindingTypeHiddenText  = "hidden_text" // 隐藏文本中的关键词
+	FindingTypeSensitive   = "sensitive"   // 敏感数据
+	FindingTypeBoilerplate = "boilerplate" // 站点公共区块中的关键词
+	FindingTypeExemption   = "exemption"   // 已过期的豁免规则
+)
+
+// Finding 表示关键词命中之外的附加发现
+type Finding struct {
+	Type     string `json:"type"`          // 发现类型
+	Severity string `json:"severity"`      // 严重程度
+	URL      string `json:"url"`           // 相关 URL 或主机
+	Key      string `json:"key,omitempty"` // 与报告语言无关的标识，用于比较两次扫描的发现
+	Title    string `json:"title"`         // 标题
+	Detail   string `json:"detail"`        // 详细说明
 }
@@ -0,0 +1,43 @@
+package model
+
+// ScanTrend 表示同一目标最近若干次扫描的统计走势
+type ScanTrend struct {
+	Days        int            `json:"days"`         // 统计的天数
+	AverageDays int            `json:"average_days"` // 计算平均值的天数
+	Points      []*TrendPoint  `json:"points"`       // 各次扫描的统计，按开始时间升序排列，最后一项为本次扫描
+	Series      []*TrendSeries `json:"series"`       // 各指标的走势
+}
+
+// TrendPoint 表示一次扫描的统计
+type TrendPoint struct {
+	StartTime       string         `json:"start_time"`               // 开始时间
+	TotalPages      int            `json:"total_pages"`              // 扫描的总页面数
+	MatchPages      int            `json:"match_pages"`              // 匹配的页面数
+	HitCount        int            `json:"hit_count"`                // 关键词总出现次数
+	ErrorCount      int            `json:"error_count"`              // 错误数
+	ErrorRate       float64        `json:"error_rate"`               // 错误页面占比（百分比）
+	DurationSeconds float64        `json:"duration_seconds"`         // 耗时（秒）
+	KeywordCounts   map[string]int `json:"keyword_counts,omitempty"` // 各关键词出现次数
+}
+
+// 走势指标
+const (
+	TrendMetricTotalPages = "total_pages" // 扫描页面数
+	TrendMetricMatchPages = "match_pages" // 匹配关键词页面数
+	TrendMetricHitCount   = "hit_count"   // 关键词总出现次数
+	TrendMetricKeyword    = "keyword"     // 单个关键词出现次数
+	TrendMetricErrorRate  = "error_rate"  // 错误页面占比
+	TrendMetricDuration   = "duration"    // 扫描耗时
+)
+
+// TrendSeries 表示一个指标在各次扫描中的取值
+type TrendSeries struct {
+	Metric      string    `json:"metric"`            // 指标
+	Keyword     string    `json:"keyword,omitempty"` // 关键词，仅 keyword 指标
+	Values      []float64 `json:"values"`            // 与 Points 一一对应的取值
+	Current     float64   `json:"current"`           // 本次扫描的取值
+	Average     float64   `json:"average"`           // 平均值统计周期内此前各次扫描的平均值
+	HasAverage  bool      `json:"has_average"`       // 平均值统计周期内是否有此前的扫描
+	Percent     float64   `json:"percent"`           // 本次相对平均值的变化百分比，平均值为 0 时为 0
+	Significant bool      `json:"significant"`       // 变化幅度是否达到阈值
+}
@@ -0,0 +1,111 @@
+package warc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reader WARC 文件读取器，自动识别 gzip 压缩
+type Reader struct {
+	br *bufio.Reader
+}
+
+// NewReader 创建读取器
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read warc header failed: %w", err)
+	}
+
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		// 每条记录为独立的 gzip 成员，gzip.Reader 默认按多成员连续读取
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("create gzip reader failed: %w", err)
+		}
+		br = bufio.NewReader(gz)
+	}
+
+	return &Reader{br: br}, nil
+}
+
+// Next 读取下一条记录，没有更多记录时返回 io.EOF
+func (r *Reader) Next() (*Record, error) {
+	// 跳过记录之间的空行
+	var line string
+	for {
+		raw, err := r.br.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) && strings.TrimSpace(raw) == "" {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("read warc version failed: %w", err)
+		}
+		if line = strings.TrimSpace(raw); line != "" {
+			break
+		}
+	}
+	if !strings.HasPrefix(line, "WARC/") {
+		return nil, fmt.Errorf("invalid warc version line: %q", line)
+	}
+
+	header, err := textproto.NewReader(r.br).ReadMIMEHeader()
+	if err != nil {
+		return nil, fmt.Errorf("read warc header failed: %w", err)
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid warc content length: %w", err)
+	}
+
+	block := make([]byte, length)
+	if _, err := io.ReadFull(r.br, block); err != nil {
+		return nil, fmt.Errorf("read warc block failed: %w", err)
+	}
+
+	record := &Record{
+		Type:         header.Get("WARC-Type"),
+		ID:           header.Get("WARC-Record-ID"),
+		TargetURI:    header.Get("WARC-Target-URI"),
+		ContentType:  header.Get("Content-Type"),
+		ConcurrentTo: header.Get("WARC-Concurrent-To"),
+		Block:        block,
+	}
+	if date, err := time.Parse(time.RFC3339Nano, header.Get("WARC-Date")); err == nil {
+		record.Date = date
+	}
+
+	known := map[string]bool{
+		"Warc-Type": true, "Warc-Record-Id": true, "Warc-Target-Uri": true, "Content-Type": true,
+		"Warc-Concurrent-To": true, "Warc-Date": true, "Content-Length": true,
+	}
+	for name, values := range header {
+		if known[name] {
+			continue
+		}
+		for _, value := range values {
+			record.Fields = append(record.Fields, [2]string{name, value})
+		}
+	}
+
+	return record, nil
+}
+
+// Field 返回指定扩展字段的值，名称不区分大小写
+func (r *Record) Field(name string) string {
+	for _, field := range r.Fields {
+		if strings.EqualFold(field[0], name) {
+			return field[1]
+		}
+	}
+	return ""
+}
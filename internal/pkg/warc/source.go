@@ -0,0 +1,50 @@
+package warc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ResolveSource 返回离线重扫读取的 WARC 文件，source 为目录时选择其中最新的 WARC 文件
+func ResolveSource(source string) (string, error) {
+	if source == "" {
+		return "", fmt.Errorf("offline source is empty")
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return source, nil
+	}
+
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return "", err
+	}
+
+	latest := ""
+	var latestModTime int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".warc") || strings.HasSuffix(name, ".warc.gz")) {
+			continue
+		}
+		entryInfo, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if modTime := entryInfo.ModTime().UnixNano(); latest == "" || modTime > latestModTime {
+			latest = filepath.Join(source, name)
+			latestModTime = modTime
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no warc file found in %s", source)
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,141 @@
+package warc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base32"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 记录类型
+const (
+	TypeWarcinfo = "warcinfo"
+	TypeRequest  = "request"
+	TypeResponse = "response"
+	TypeResource = "resource"
+)
+
+const version = "WARC/1.1"
+
+// Record WARC 记录
+type Record struct {
+	Type         string      // WARC-Type
+	ID           string      // WARC-Record-ID，为空时自动生成
+	TargetURI    string      // WARC-Target-URI
+	Date         time.Time   // WARC-Date，为零值时使用当前时间
+	ContentType  string      // Content-Type
+	ConcurrentTo string      // WARC-Concurrent-To
+	Payload      []byte      // 负载内容，非空时计算 WARC-Payload-Digest
+	Fields       [][2]string // 其他头部字段，按顺序输出
+	Block        []byte      // 记录内容
+}
+
+// Writer WARC 文件写入器，并发安全；开启压缩时每条记录为独立的 gzip 成员
+type Writer struct {
+	mu       sync.Mutex
+	file     *os.File
+	compress bool
+}
+
+// NewWriter 创建 WARC 文件
+func NewWriter(filePath string, compress bool) (*Writer, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("create warc file failed: %w", err)
+	}
+
+	return &Writer{
+		file:     file,
+		compress: compress,
+	}, nil
+}
+
+// WriteRecord 写入一条记录，返回记录 ID
+func (w *Writer) WriteRecord(record *Record) (string, error) {
+	if record.ID == "" {
+		record.ID = NewRecordID()
+	}
+	if record.Date.IsZero() {
+		record.Date = time.Now()
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(version + "\r\n")
+	writeField(&buf, "WARC-Type", record.Type)
+	writeField(&buf, "WARC-Record-ID", record.ID)
+	writeField(&buf, "WARC-Date", record.Date.UTC().Format(time.RFC3339))
+	writeField(&buf, "WARC-Target-URI", record.TargetURI)
+	writeField(&buf, "WARC-Concurrent-To", record.ConcurrentTo)
+	writeField(&buf, "Content-Type", record.ContentType)
+	if record.Payload != nil {
+		writeField(&buf, "WARC-Payload-Digest", Digest(record.Payload))
+	}
+	writeField(&buf, "WARC-Block-Digest", Digest(record.Block))
+	for _, field := range record.Fields {
+		writeField(&buf, field[0], field[1])
+	}
+	writeField(&buf, "Content-Length", fmt.Sprint(len(record.Block)))
+	buf.WriteString("\r\n")
+	buf.Write(record.Block)
+	buf.WriteString("\r\n\r\n")
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.write(buf.Bytes()); err != nil {
+		return "", fmt.Errorf("write warc record failed: %w", err)
+	}
+	return record.ID, nil
+}
+
+func (w *Writer) write(data []byte) error {
+	if !w.compress {
+		_, err := w.file.Write(data)
+		return err
+	}
+
+	gz := gzip.NewWriter(w.file)
+	if _, err := gz.Write(data); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Close 关闭文件
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// Name 返回文件路径
+func (w *Writer) Name() string {
+	return w.file.Name()
+}
+
+func writeField(buf *bytes.Buffer, name, value string) {
+	if value == "" {
+		return
+	}
+	buf.WriteString(name)
+	buf.WriteString(": ")
+	buf.WriteString(value)
+	buf.WriteString("\r\n")
+}
+
+// Digest 计算 WARC 使用的 sha1 摘要（base32 编码）
+func Digest(data []byte) string {
+	sum := sha1.Sum(data)
+	return "sha1:" + base32.StdEncoding.EncodeToString(sum[:])
+}
+
+// NewRecordID 生成 urn:uuid 形式的记录 ID
+func NewRecordID() string {
+	return "<urn:uuid:" + uuid.NewString() + ">"
+}
@@ -0,0 +1,376 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// 单元格文本的最大长度（按 UTF-16 编码单元计算），超出部分会被 Excel 拒绝打开
+const maxCellChars = 32767
+
+// 样式编号，对应 styles.xml 中的 cellXfs
+const (
+	styleDefault = 0
+	styleHeader  = 1
+	styleWrap    = 2
+)
+
+// Column 列设置
+type Column struct {
+	Width float64 // 列宽（字符数），0 表示使用默认宽度
+	Wrap  bool    // 是否自动换行
+}
+
+// Sheet 工作表，第一行作为表头时会加粗、冻结并开启筛选
+type Sheet struct {
+	name        string
+	header      []string
+	rows        [][]interface{}
+	columns     []Column
+	validations []*listValidation
+}
+
+// listValidation 下拉列表形式的数据验证
+type listValidation struct {
+	column  int
+	options []string
+}
+
+// Workbook 工作簿，写入前全部内容保存在内存中
+type Workbook struct {
+	sheets []*Sheet
+}
+
+// NewWorkbook 创建空工作簿
+func NewWorkbook() *Workbook {
+	return &Workbook{}
+}
+
+// AddSheet 添加工作表，名称中 Excel 不允许的字符会被替换
+func (wb *Workbook) AddSheet(name string) *Sheet {
+	sheet := &Sheet{name: sheetName(name, len(wb.sheets)+1)}
+	wb.sheets = append(wb.sheets, sheet)
+	return sheet
+}
+
+// SetHeader 设置表头
+func (s *Sheet) SetHeader(header ...string) {
+	s.header = header
+}
+
+// SetColumns 按顺序设置各列的宽度与换行
+func (s *Sheet) SetColumns(columns ...Column) {
+	s.columns = columns
+}
+
+// AddRow 添加一行，支持字符串、整数、浮点数与布尔值，其他类型按 fmt 格式化为文本
+func (s *Sheet) AddRow(values ...interface{}) {
+	s.rows = append(s.rows, values)
+}
+
+// AddListValidation 为某一列（从 0 开始）的数据行添加下拉选项
+func (s *Sheet) AddListValidation(column int, options ...string) {
+	s.validations = append(s.validations, &listValidation{column: column, options: options})
+}
+
+// Write 将工作簿以 xlsx 格式写入 w
+func (wb *Workbook) Write(w io.Writer) error {
+	sheets := wb.sheets
+	if len(sheets) == 0 {
+		sheets = []*Sheet{{name: "Sheet1"}}
+	}
+
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", contentTypesXML(len(sheets))},
+		{"_rels/.rels", []byte(rootRelsXML)},
+		{"xl/workbook.xml", workbookXML(sheets)},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML(len(sheets))},
+		{"xl/styles.xml", []byte(stylesXML)},
+	}
+	for i, sheet := range sheets {
+		files = append(files, struct {
+			name    string
+			content []byte
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet.xml()})
+	}
+
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("create %s failed: %w", file.name, err)
+		}
+		if _, err := fw.Write(file.content); err != nil {
+			return fmt.Errorf("write %s failed: %w", file.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("close xlsx failed: %w", err)
+	}
+	return nil
+}
+
+// xml 生成工作表内容
+func (s *Sheet) xml() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+
+	if len(s.header) > 0 {
+		buf.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	}
+
+	if len(s.columns) > 0 {
+		buf.WriteString("<cols>")
+		for i, col := range s.columns {
+			width := col.Width
+			if width <= 0 {
+				width = 10
+			}
+			buf.WriteString(fmt.Sprintf(`<col min="%d" max="%d" width="%s" customWidth="1"`, i+1, i+1, strconv.FormatFloat(width, 'f', -1, 64)))
+			if col.Wrap {
+				buf.WriteString(fmt.Sprintf(` style="%d"`, styleWrap))
+			}
+			buf.WriteString("/>")
+		}
+		buf.WriteString("</cols>")
+	}
+
+	buf.WriteString("<sheetData>")
+	rowNum := 0
+	maxCols := len(s.header)
+	if len(s.header) > 0 {
+		rowNum++
+		values := make([]interface{}, len(s.header))
+		for i, h := range s.header {
+			values[i] = h
+		}
+		s.writeRow(&buf, rowNum, values, true)
+	}
+	for _, row := range s.rows {
+		rowNum++
+		s.writeRow(&buf, rowNum, row, false)
+		if len(row) > maxCols {
+			maxCols = len(row)
+		}
+	}
+	buf.WriteString("</sheetData>")
+
+	if len(s.header) > 0 {
+		buf.WriteString(fmt.Sprintf(`<autoFilter ref="A1:%s%d"/>`, columnName(len(s.header)-1), rowNum))
+	}
+
+	if len(s.validations) > 0 {
+		buf.WriteString(fmt.Sprintf(`<dataValidations count="%d">`, len(s.validations)))
+		firstRow := 1
+		if len(s.header) > 0 {
+			firstRow = 2
+		}
+		for _, v := range s.validations {
+			col := columnName(v.column)
+			buf.WriteString(fmt.Sprintf(`<dataValidation type="list" allowBlank="1" showErrorMessage="1" sqref="%s%d:%s1048576"><formula1>`,
+				col, firstRow, col))
+			writeEscaped(&buf, `"`+strings.Join(v.options, ",")+`"`)
+			buf.WriteString("</formula1></dataValidation>")
+		}
+		buf.WriteString("</dataValidations>")
+	}
+
+	buf.WriteString("</worksheet>")
+	return buf.Bytes()
+}
+
+// writeRow 写入一行单元格，nil 值对应空单元格
+func (s *Sheet) writeRow(buf *bytes.Buffer, rowNum int, values []interface{}, header bool) {
+	buf.WriteString(fmt.Sprintf(`<row r="%d">`, rowNum))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		ref := fmt.Sprintf("%s%d", columnName(i), rowNum)
+		style := styleDefault
+		if header {
+			style = styleHeader
+		} else if i < len(s.columns) && s.columns[i].Wrap {
+			style = styleWrap
+		}
+		styleAttr := ""
+		if style != styleDefault {
+			styleAttr = fmt.Sprintf(` s="%d"`, style)
+		}
+
+		switch v := value.(type) {
+		case int:
+			buf.WriteString(fmt.Sprintf(`<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v))
+		case int64:
+			buf.WriteString(fmt.Sprintf(`<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v))
+		case float64:
+			buf.WriteString(fmt.Sprintf(`<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, strconv.FormatFloat(v, 'f', -1, 64)))
+		case bool:
+			b := 0
+			if v {
+				b = 1
+			}
+			buf.WriteString(fmt.Sprintf(`<c r="%s"%s t="b"><v>%d</v></c>`, ref, styleAttr, b))
+		default:
+			text, ok := value.(string)
+			if !ok {
+				text = fmt.Sprint(value)
+			}
+			if text == "" {
+				continue
+			}
+			// 使用内联字符串，以 = 开头的文本不会被当作公式执行
+			buf.WriteString(fmt.Sprintf(`<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">`, ref, styleAttr))
+			writeEscaped(buf, cellText(text))
+			buf.WriteString("</t></is></c>")
+		}
+	}
+	buf.WriteString("</row>")
+}
+
+// cellText 去除 XML 不允许的控制字符并截断超长文本，无效的 UTF-8 字节替换为 U+FFFD
+func cellText(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return r
+		}
+		if r < 0x20 || r == 0xFFFE || r == 0xFFFF {
+			return -1
+		}
+		return r
+	}, text)
+
+	// Excel 按 UTF-16 计算长度，基本多文种平面之外的字符占两个单元
+	units := 0
+	for i, r := range text {
+		n := utf16.RuneLen(r)
+		if units+n > maxCellChars {
+			return text[:i]
+		}
+		units += n
+	}
+	return text
+}
+
+// writeEscaped 写入转义后的 XML 文本
+func writeEscaped(buf *bytes.Buffer, text string) {
+	_ = xml.EscapeText(buf, []byte(text))
+}
+
+// columnName 将从 0 开始的列号转换为 A、B、…、AA 形式
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// sheetName 替换工作表名称中不允许的字符并限制为 31 个字符
+func sheetName(name string, index int) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if utf8.RuneCountInString(name) > 31 {
+		name = string([]rune(name)[:31])
+	}
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", index)
+	}
+	return name
+}
+
+func contentTypesXML(sheetCount int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	buf.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	buf.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	buf.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	buf.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheetCount; i++ {
+		buf.WriteString(fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i))
+	}
+	buf.WriteString(`</Types>`)
+	return buf.Bytes()
+}
+
+func workbookXML(sheets []*Sheet) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sheet := range sheets {
+		buf.WriteString(`<sheet name="`)
+		writeEscaped(&buf, sheet.name)
+		buf.WriteString(fmt.Sprintf(`" sheetId="%d" r:id="rId%d"/>`, i+1, i+1))
+	}
+	buf.WriteString(`</sheets>`)
+
+	// 表头筛选需要对应的隐藏名称才能被所有版本的 Excel 识别
+	names := make([]string, 0)
+	for i, sheet := range sheets {
+		if len(sheet.header) == 0 {
+			continue
+		}
+		rows := len(sheet.rows) + 1
+		names = append(names, fmt.Sprintf(`<definedName name="_xlnm._FilterDatabase" localSheetId="%d" hidden="1">'%s'!$A$1:$%s$%d</definedName>`,
+			i, escapeAttr(strings.ReplaceAll(sheet.name, "'", "''")), columnName(len(sheet.header)-1), rows))
+	}
+	if len(names) > 0 {
+		buf.WriteString("<definedNames>" + strings.Join(names, "") + "</definedNames>")
+	}
+
+	buf.WriteString(`</workbook>`)
+	return buf.Bytes()
+}
+
+func workbookRelsXML(sheetCount int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheetCount; i++ {
+		buf.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i))
+	}
+	buf.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheetCount+1))
+	buf.WriteString(`</Relationships>`)
+	return buf.Bytes()
+}
+
+func escapeAttr(text string) string {
+	var buf bytes.Buffer
+	writeEscaped(&buf, text)
+	return buf.String()
+}
+
+const rootRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const stylesXML = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FFDDEBF7"/><bgColor indexed="64"/></patternFill></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="2" borderId="0" xfId="0" applyFont="1" applyFill="1"/>` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0" applyAlignment="1"><alignment vertical="top" wrapText="1"/></xf>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`